@@ -0,0 +1,132 @@
+package funtemplates
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+func SumTemplateFunc(slice any, f ...any) (any, error) {
+	return defaultOperations.sum(slice, f...)
+}
+
+func ProductTemplateFunc(slice any, f ...any) (any, error) {
+	return defaultOperations.product(slice, f...)
+}
+
+func AvgTemplateFunc(slice any, f ...any) (any, error) {
+	return defaultOperations.avg(slice, f...)
+}
+
+func (o *operations) sum(slice any, f ...any) (any, error) {
+	r, err := o.aggregate(slice, f, '+')
+	if err != nil {
+		return nil, opError("sum", firstOf(f), err)
+	}
+	return r, nil
+}
+
+func (o *operations) product(slice any, f ...any) (any, error) {
+	r, err := o.aggregate(slice, f, '*')
+	if err != nil {
+		return nil, opError("product", firstOf(f), err)
+	}
+	return r, nil
+}
+
+func (o *operations) avg(slice any, f ...any) (any, error) {
+	r, err := o.average(slice, f)
+	if err != nil {
+		return nil, opError("avg", firstOf(f), err)
+	}
+	return r, nil
+}
+
+func (o *operations) average(slice any, f []any) (any, error) {
+	total, n, _, err := o.accumulate(slice, f, '+')
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrEmptySlice
+	}
+	switch total.kind {
+	case numBigInt:
+		return new(big.Rat).SetFrac(total.bi, big.NewInt(int64(n))), nil
+	case numBigRat:
+		return new(big.Rat).Quo(total.br, new(big.Rat).SetInt64(int64(n))), nil
+	case numBigFloat:
+		return new(big.Float).Quo(total.bf, new(big.Float).SetInt64(int64(n))), nil
+	}
+	return total.float() / float64(n), nil
+}
+
+func (o *operations) aggregate(slice any, f []any, op byte) (any, error) {
+	total, _, t, err := o.accumulate(slice, f, op)
+	if err != nil {
+		return nil, err
+	}
+	return total.value(t)
+}
+
+// accumulate folds op over the elements of slice, or the results of the optional key function. It also returns the
+// number of elements and the static type of the values folded.
+func (o *operations) accumulate(slice any, f []any, op byte) (number, int, reflect.Type, error) {
+	identity := number{kind: numInt}
+	if op == '*' {
+		identity.i = 1
+	}
+	if len(f) > 1 {
+		return number{}, 0, nil, fmt.Errorf("%w got: %d", ErrExpectedAtMostOneCallback, len(f))
+	}
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return number{}, 0, nil, err
+	}
+	var c *callback
+	var t reflect.Type
+	if len(f) == 1 {
+		if c, err = o.newCallback(f[0], 1); err != nil {
+			return number{}, 0, nil, err
+		}
+		t = c.out
+	} else if av.IsValid() {
+		t = av.Type().Elem()
+	}
+	if t != nil && t.Kind() != reflect.Interface {
+		// Keep the identity in the same family as the elements so typed results need no promotion.
+		switch orderClassOf(reflect.Zero(t)) {
+		case orderUint:
+			identity = number{kind: numUint, u: uint64(identity.i)}
+		case orderFloat:
+			identity = number{kind: numFloat, f: float64(identity.i)}
+		}
+	}
+	total := identity
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return number{}, 0, nil, err
+		}
+		ev := av.Index(i)
+		if c != nil {
+			if ev, err = c.call(i, ev); err != nil {
+				return number{}, 0, nil, err
+			}
+		}
+		n, err := numberOf(ev)
+		if err != nil {
+			return number{}, 0, nil, elementError(i, av.Index(i), err)
+		}
+		if total, err = combine(total, n, op); err != nil {
+			return number{}, 0, nil, elementError(i, av.Index(i), err)
+		}
+	}
+	return total, l, t, nil
+}
+
+func firstOf(args []any) any {
+	if len(args) == 0 {
+		return nil
+	}
+	return args[0]
+}
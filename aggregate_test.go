@@ -0,0 +1,217 @@
+package funtemplates
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"math"
+	"math/big"
+	"testing"
+	"text/template"
+)
+
+type lineItem struct {
+	Qty   int
+	Price float64
+}
+
+func TestAggregateTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Sum of ints",
+			template:   "{{ sum $.DataInts }}",
+			want:       "10",
+			correctErr: NoError,
+		},
+		{
+			name:       "Product of ints",
+			template:   "{{ product $.DataInts }}",
+			want:       "24",
+			correctErr: NoError,
+		},
+		{
+			name:       "Average of ints is a float",
+			template:   "{{ avg $.DataInts }}",
+			want:       "2.5",
+			correctErr: NoError,
+		},
+		{
+			name:       "Sum keeps the element type",
+			template:   "{{ printf \"%T %v\" (sum $.DataUint8s) (sum $.DataUint8s) }}",
+			want:       "uint8 40",
+			correctErr: NoError,
+		},
+		{
+			name:       "Sum of empty slice",
+			template:   "{{ sum $.Empty }} {{ product $.Empty }}",
+			want:       "0 1",
+			correctErr: NoError,
+		},
+		{
+			name:       "Average of empty slice",
+			template:   "{{ avg $.Empty }}",
+			want:       "",
+			correctErr: ErrorIs(ErrEmptySlice),
+		},
+		{
+			name:       "Sum of nil",
+			template:   "{{ sum nil }}",
+			want:       "0",
+			correctErr: NoError,
+		},
+		{
+			name:       "Mixed values are promoted",
+			template:   "{{ sum $.DataMixed }}",
+			want:       "7.5",
+			correctErr: NoError,
+		},
+		{
+			name:       "Mixed ints and uints stay integers",
+			template:   "{{ printf \"%T %v\" (sum $.DataMixedInts) (sum $.DataMixedInts) }}",
+			want:       "int 3",
+			correctErr: NoError,
+		},
+		{
+			name:       "JSON numbers",
+			template:   "{{ sum $.DataJSON }}",
+			want:       "4.5",
+			correctErr: NoError,
+		},
+		{
+			name:       "Sum with key function",
+			template:   "{{ sum $.Items $.Funcs.total }}",
+			want:       "7.5",
+			correctErr: NoError,
+		},
+		{
+			name:       "Average with key function",
+			template:   "{{ avg $.Items $.Funcs.qty }}",
+			want:       "2",
+			correctErr: NoError,
+		},
+		{
+			name:       "Big ints",
+			template:   "{{ sum $.DataBigInts }} {{ avg $.DataBigInts }}",
+			want:       "36893488147419103232 18446744073709551616/1",
+			correctErr: NoError,
+		},
+		{
+			name:       "Big rats with ints",
+			template:   "{{ sum $.DataBigRats }}",
+			want:       "7/6",
+			correctErr: NoError,
+		},
+		{
+			name:       "Integer overflow",
+			template:   "{{ sum $.DataHuge }}",
+			want:       "",
+			correctErr: ErrorIs(ErrIntegerOverflow),
+		},
+		{
+			name:       "Integer overflow of element type",
+			template:   "{{ product $.DataUint8s }}",
+			want:       "",
+			correctErr: ErrorIs(ErrIntegerOverflow),
+		},
+		{
+			name:       "Strings are not numbers",
+			template:   "{{ sum $.DataStrings }}",
+			want:       "",
+			correctErr: ErrorIs(ErrNotANumber),
+		},
+		{
+			name:       "NaN with big numbers",
+			template:   "{{ sum $.DataBigNaN }}",
+			want:       "",
+			correctErr: ErrorIs(ErrNotANumber),
+		},
+		{
+			name:       "Infinity with big numbers",
+			template:   "{{ product $.DataBigInf }}",
+			want:       "",
+			correctErr: ErrorIs(ErrNotANumber),
+		},
+		{
+			name:       "Opposite big infinities",
+			template:   "{{ sum $.DataBigInfs }}",
+			want:       "",
+			correctErr: ErrorIs(ErrNotANumber),
+		},
+		{
+			name:       "Too many functions",
+			template:   "{{ sum $.Items $.Funcs.qty $.Funcs.qty }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedAtMostOneCallback),
+		},
+		{
+			name:       "First parameter must be a slice not a number",
+			template:   "{{ sum 123 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	twoTo64, _ := new(big.Int).SetString("18446744073709551616", 10)
+	data := struct {
+		DataInts      []int
+		DataUint8s    []uint8
+		DataMixed     []any
+		DataMixedInts []any
+		DataJSON      []any
+		DataBigInts   []*big.Int
+		DataBigRats   []any
+		DataHuge      []int64
+		DataStrings   []string
+		DataBigNaN    []any
+		DataBigInf    []any
+		DataBigInfs   []*big.Float
+		Items         []lineItem
+		Empty         []int
+		Funcs         map[string]any
+	}{
+		DataInts:      []int{1, 2, 3, 4},
+		DataUint8s:    []uint8{10, 30},
+		DataMixed:     []any{1, uint(2), 4.5},
+		DataMixedInts: []any{int8(-1), uint64(4)},
+		DataJSON:      []any{json.Number("2"), json.Number("2.5")},
+		DataBigInts:   []*big.Int{twoTo64, twoTo64},
+		DataBigRats:   []any{big.NewRat(1, 6), 1},
+		DataHuge:      []int64{math.MaxInt64, 1},
+		DataStrings:   []string{"a"},
+		DataBigNaN:    []any{big.NewInt(1), math.NaN()},
+		DataBigInf:    []any{big.NewInt(2), math.Inf(1)},
+		DataBigInfs:   []*big.Float{new(big.Float).SetInf(false), new(big.Float).SetInf(true)},
+		Items:         []lineItem{{Qty: 1, Price: 1.5}, {Qty: 3, Price: 2}},
+		Empty:         []int{},
+		Funcs: map[string]any{
+			"total": func(l lineItem) float64 { return float64(l.Qty) * l.Price },
+			"qty":   func(l lineItem) int { return l.Qty },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("SumTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("SumTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package funtemplates
+
+import (
+	"fmt"
+)
+
+// Budget limits the work the functions of one FuncMap may do, so templates from untrusted authors cannot exhaust
+// memory or CPU. A zero field is no limit. Calls are counted per template execution, over every operation of the
+// Functions returned by Functions.ForExecution, so with MaxCalls templates must be executed with those. The functions
+// of New are then only for parsing, and fail with ErrNotForExecution when a callback is called.
+type Budget struct {
+	// MaxInput is the most elements an operation may be given in one slice or iter.Seq
+	MaxInput int
+	// MaxCalls is the most times callbacks may be called
+	MaxCalls int
+	// MaxResult is the most elements an operation generating them may return. It is checked by seq, the
+	// combinatorics, transpose, the set operations, take and collect, the others returning at most one element per
+	// element given.
+	MaxResult int
+}
+
+// WithBudget limits the functions to b. Exceeding it fails the operation with an error wrapping ErrBudgetExceeded,
+// which the error policy does not apply to. TextFunctions and HtmlFunctions panic with ErrNotForExecution when b
+// has MaxCalls, use New and ForExecution.
+func WithBudget(b Budget) Option {
+	return func(o *operations) {
+		o.budget = b
+	}
+}
+
+// checkInput returns an error when an operation is given l elements, or is still reading them at l.
+func (o *operations) checkInput(l int) error {
+	if o.budget.MaxInput > 0 && l > o.budget.MaxInput {
+		return fmt.Errorf("%w: more than %d elements given", ErrBudgetExceeded, o.budget.MaxInput)
+	}
+	return nil
+}
+
+// checkResult returns an error when an operation would return l elements.
+func (o *operations) checkResult(l int) error {
+	if o.budget.MaxResult > 0 && l > o.budget.MaxResult {
+		return fmt.Errorf("%w: more than %d elements returned", ErrBudgetExceeded, o.budget.MaxResult)
+	}
+	return nil
+}
+
+// countCall is called before each callback call, returning an error once there have been too many.
+func (o *operations) countCall() error {
+	if o.budget.MaxCalls <= 0 {
+		return nil
+	}
+	if o.calls == nil {
+		return fmt.Errorf("%w: MaxCalls is counted per execution, use the functions of ForExecution", ErrNotForExecution)
+	}
+	if o.calls.Add(1) > int64(o.budget.MaxCalls) {
+		return fmt.Errorf("%w: more than %d callback calls", ErrBudgetExceeded, o.budget.MaxCalls)
+	}
+	return nil
+}
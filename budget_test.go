@@ -0,0 +1,206 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestWithBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		budget     Budget
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Within budget",
+			budget:     Budget{MaxInput: 5, MaxCalls: 10, MaxResult: 5},
+			template:   "{{ map $.Data $.Double }} {{ filter $.Data $.Even }}",
+			want:       "[2 4 6 8 10] [2 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "map input too long",
+			budget:     Budget{MaxInput: 4},
+			template:   "{{ map $.Data $.Double }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "map fast path input too long",
+			budget:     Budget{MaxInput: 4},
+			template:   "{{ map $.Ints $.DoubleInt }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "sum input too long",
+			budget:     Budget{MaxInput: 4},
+			template:   "{{ sum $.Data }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "Iterator input too long",
+			budget:     Budget{MaxInput: 100},
+			template:   "{{ map (seqIter 1000000000) $.Double }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "Stream input too long",
+			budget:     Budget{MaxInput: 100},
+			template:   "{{ collect (lazyFilter (seqIter 1000000000) $.Never) }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "take reads no more than it needs",
+			budget:     Budget{MaxInput: 100},
+			template:   "{{ take (lazyMap (seqIter 1000000000) $.Double) 3 }}",
+			want:       "[0 2 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Calls are counted across operations",
+			budget:     Budget{MaxCalls: 8},
+			template:   "{{ map $.Data $.Double }} {{ filter $.Data $.Even }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "Calls are counted by every operation",
+			budget:     Budget{MaxCalls: 4},
+			template:   "{{ sum $.Data $.Double }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "Calls are counted by pmap",
+			budget:     Budget{MaxCalls: 4},
+			template:   "{{ pmap $.Data $.Double }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "Exceeding the budget is not an element error",
+			budget:     Budget{MaxCalls: 4},
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ map $.Data $.Double }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "mapTry fails when exceeding the budget",
+			budget:     Budget{MaxCalls: 4},
+			template:   "{{ mapTry $.Data $.Double }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "seq result too long",
+			budget:     Budget{MaxResult: 100},
+			template:   "{{ seq 101 }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "combinations result too long",
+			budget:     Budget{MaxResult: 9},
+			template:   "{{ combinations $.Data 2 }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "union result too long",
+			budget:     Budget{MaxResult: 6},
+			template:   "{{ union $.Data (seq 10 20) }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "collect result too long",
+			budget:     Budget{MaxResult: 4},
+			template:   "{{ collect (lazyMap $.Data $.Double) }}",
+			correctErr: ErrorIs(ErrBudgetExceeded),
+		},
+		{
+			name:       "No budget",
+			template:   "{{ len (seq 100000) }}",
+			want:       "100000",
+			correctErr: NoError,
+		},
+	}
+	data := map[string]any{
+		"Data":      []int{1, 2, 3, 4, 5},
+		"Ints":      []int{1, 2, 3, 4, 5},
+		"Double":    func(i int) (int, error) { return i * 2, nil },
+		"DoubleInt": func(i int) int { return i * 2 },
+		"Even":      func(i int) bool { return i%2 == 0 },
+		"Never":     func(i int) bool { return false },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(append([]Option{WithBudget(tt.budget)}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tmpl := template.Must(template.New("").Funcs(f.ForExecution().Text()).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err = tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("budget got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("budget diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestBudgetErrorIsAnOpError(t *testing.T) {
+	mapFunc := TextFunctions(WithBudget(Budget{MaxInput: 2}))["map"].(func(any, any) (any, error))
+	_, err := mapFunc([]string{"a", "b", "c"}, func(s string) string { return s })
+	var oe *OpError
+	if !errors.Is(err, ErrBudgetExceeded) || !errors.As(err, &oe) || oe.Op != "map" {
+		t.Errorf("expected a map OpError wrapping ErrBudgetExceeded, got %v", err)
+	}
+}
+
+func TestForExecutionHasItsOwnBudget(t *testing.T) {
+	f, err := New(WithBudget(Budget{MaxCalls: 5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]any{
+		"Data":   []int{1, 2, 3},
+		"Double": func(i int) (int, error) { return i * 2, nil },
+	}
+	tmpl := template.Must(template.New("").Funcs(f.Text()).Parse("{{ map $.Data $.Double }}"))
+	for i := 0; i < 3; i++ {
+		t.Run(fmt.Sprintf("Execution %d", i), func(t *testing.T) {
+			clone := template.Must(tmpl.Clone())
+			clone.Funcs(f.ForExecution().Text())
+			got := bytes.NewBuffer(nil)
+			if err := clone.Execute(got, data); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff("[2 4 6]", got.String()); diff != "" {
+				t.Errorf("budget diff =\n %s", diff)
+			}
+		})
+	}
+
+	if err := tmpl.Execute(bytes.NewBuffer(nil), data); !errors.Is(err, ErrNotForExecution) {
+		t.Errorf("expected the functions of New to refuse to call callbacks with MaxCalls, got %v", err)
+	}
+}
+
+func TestTextFunctionsRefusesMaxCalls(t *testing.T) {
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrNotForExecution) {
+			t.Errorf("expected a panic with ErrNotForExecution, got %v", err)
+		}
+	}()
+	TextFunctions(WithBudget(Budget{MaxCalls: 5}))
+}
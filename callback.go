@@ -0,0 +1,120 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var boolType = reflect.TypeOf(true)
+
+// callback is a validated user supplied function taking 0 or more element parameters and returning a value and an
+// optional error.
+type callback struct {
+	o      *operations
+	fv     reflect.Value
+	p      *plan
+	in     []reflect.Type
+	out    reflect.Type
+	hasErr bool
+}
+
+func (o *operations) sliceArg(slice any) (reflect.Value, int, error) {
+	av, err := o.seqToSlice(reflect.ValueOf(slice))
+	if err != nil {
+		return av, 0, err
+	}
+	if av.Kind() != reflect.Slice && av.Kind() != reflect.Invalid {
+		return av, 0, fmt.Errorf("%w not %s", ErrExpectedFirstParameterToBeSlice, av.Kind())
+	}
+	l := 0
+	if av.Kind() != reflect.Invalid && !av.IsNil() {
+		l = av.Len()
+	}
+	return av, l, nil
+}
+
+// newCallback validates f as a function of up to maxIn parameters, not counting a leading context.Context.
+func (o *operations) newCallback(f any, maxIn int) (*callback, error) {
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func {
+		return nil, ErrExpected2ndArgumentToBeFunction
+	}
+	p := planFor(fv.Type(), nil)
+	if len(p.in) > maxIn {
+		if maxIn == 1 {
+			return nil, ErrInputFuncMustTake0or1Arguments
+		}
+		return nil, fmt.Errorf("%w got: %d", ErrInputFuncTooManyArguments, len(p.in))
+	}
+	switch p.numOut {
+	case 1, 2:
+		if p.outErr != nil {
+			return nil, p.outErr
+		}
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
+	}
+	return &callback{o: o, fv: fv, p: p, in: p.in, out: p.out, hasErr: p.hasErr}, nil
+}
+
+func (o *operations) newPredicate(f any) (*callback, error) {
+	c, err := o.newCallback(f, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !c.out.AssignableTo(boolType) {
+		return nil, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, c.out)
+	}
+	return c, nil
+}
+
+// call invokes the callback for item i, the last of args. Surplus args are dropped for functions which take fewer
+// parameters.
+func (c *callback) call(i int, args ...reflect.Value) (reflect.Value, error) {
+	in := make([]reflect.Value, len(c.in))
+	for n, t := range c.in {
+		arg, err := c.o.argument(i, args[n], t)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		in[n] = arg
+	}
+	r, err := c.o.call(c.fv, c.p, i, args[len(args)-1], in)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if c.hasErr && !r[1].IsNil() {
+		return reflect.Value{}, callbackError(i, args[len(args)-1], r[1].Interface().(error))
+	}
+	return r[0], nil
+}
+
+func (c *callback) test(i int, args ...reflect.Value) (bool, error) {
+	r, err := c.call(i, args...)
+	if err != nil {
+		return false, err
+	}
+	return r.Bool(), nil
+}
+
+// argumentFor unwraps interface values and checks v can be passed as a parameter of type t.
+func argumentFor(i int, v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			switch t.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Func, reflect.Interface, reflect.Chan:
+				return reflect.Zero(t), nil
+			default:
+				return reflect.Value{}, elementError(i, v, fmt.Errorf("is nil, %w: %s", ErrItemNotAssignable, t))
+			}
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return reflect.Zero(t), nil
+	}
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, notAssignableError(i, v, t)
+	}
+	return v, nil
+}
@@ -0,0 +1,440 @@
+package funtemplates
+
+import (
+	"fmt"
+	ht "html/template"
+	"reflect"
+	tt "text/template"
+	"text/template/parse"
+)
+
+// Diagnostic is a problem found by CheckText or CheckHtml.
+type Diagnostic struct {
+	// Location is the name of the template, line and column of the problem, as in text/template's errors
+	Location string
+	// Context is the text of the node with the problem
+	Context string
+	// Op is the operation with the problem, empty when it is in a field chain
+	Op  string
+	Err error
+}
+
+func (d *Diagnostic) Error() string {
+	if d.Op == "" {
+		return fmt.Sprintf("%s: <%s>: %v", d.Location, d.Context, d.Err)
+	}
+	return fmt.Sprintf("%s: <%s>: %s: %v", d.Location, d.Context, d.Op, d.Err)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// CheckText finds the errors Execute would return because of the callbacks passed to the operations, without
+// executing t. data is the type of the data t will be executed with. It follows the types of fields, variables,
+// range and with through t and the templates it invokes, and checks the callbacks of map, filter, find, findIndex
+// and their variants against the elements of the slices they are given. Types which are only known when executing,
+// such as those of interfaces or map[string]any values, are not checked. opts are the options the functions are
+// built with, so renamed operations are found and coercion is allowed for.
+func CheckText(t *tt.Template, data reflect.Type, opts ...Option) []*Diagnostic {
+	return check(t.Tree, data, func(name string) *parse.Tree {
+		if t := t.Lookup(name); t != nil {
+			return t.Tree
+		}
+		return nil
+	}, opts)
+}
+
+// CheckHtml is CheckText for html/template. It must be called before t is executed.
+func CheckHtml(t *ht.Template, data reflect.Type, opts ...Option) []*Diagnostic {
+	return check(t.Tree, data, func(name string) *parse.Tree {
+		if t := t.Lookup(name); t != nil {
+			return t.Tree
+		}
+		return nil
+	}, opts)
+}
+
+// checkedOperations are the operations whose callback is checked, true for those taking a predicate.
+var checkedOperations = map[string]bool{
+	"map":        false,
+	"mapTry":     false,
+	"pmap":       false,
+	"lazyMap":    false,
+	"filter":     true,
+	"filterTry":  true,
+	"pfilter":    true,
+	"lazyFilter": true,
+	"find":       true,
+	"findIndex":  true,
+	"findResult": true,
+}
+
+type checker struct {
+	o *operations
+	// ops holds the operation registered under each name
+	ops    map[string]string
+	lookup func(name string) *parse.Tree
+	tree   *parse.Tree
+	vars   []variable
+	// checked holds the templates already checked with each type of dot
+	checked map[checkedTemplate]bool
+	diags   []*Diagnostic
+}
+
+type variable struct {
+	name string
+	t    reflect.Type
+}
+
+type checkedTemplate struct {
+	name string
+	dot  reflect.Type
+}
+
+func check(tree *parse.Tree, data reflect.Type, lookup func(name string) *parse.Tree, opts []Option) []*Diagnostic {
+	if tree == nil {
+		return nil
+	}
+	o := newOperations(opts...)
+	names, err := o.templateNames()
+	if err != nil {
+		return []*Diagnostic{{Location: tree.ParseName, Err: err}}
+	}
+	c := &checker{o: o, ops: map[string]string{}, lookup: lookup, checked: map[checkedTemplate]bool{}}
+	for op, name := range names {
+		c.ops[name] = op
+	}
+	c.template(tree, data)
+	return c.diags
+}
+
+// template checks tree executed with dot.
+func (c *checker) template(tree *parse.Tree, dot reflect.Type) {
+	k := checkedTemplate{name: tree.Name, dot: dot}
+	if c.checked[k] {
+		return
+	}
+	c.checked[k] = true
+	outer, vars := c.tree, c.vars
+	c.tree, c.vars = tree, []variable{{name: "$", t: dot}}
+	c.walk(tree.Root, dot)
+	c.tree, c.vars = outer, vars
+}
+
+func (c *checker) report(n parse.Node, op string, err error) {
+	location, context := c.tree.ErrorContext(n)
+	c.diags = append(c.diags, &Diagnostic{Location: location, Context: context, Op: op, Err: err})
+}
+
+func (c *checker) walk(n parse.Node, dot reflect.Type) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, n := range n.Nodes {
+			c.walk(n, dot)
+		}
+	case *parse.ActionNode:
+		c.declare(n.Pipe, c.pipe(n.Pipe, dot))
+	case *parse.IfNode:
+		scope := len(c.vars)
+		c.declare(n.Pipe, c.pipe(n.Pipe, dot))
+		c.walk(n.List, dot)
+		c.vars = c.vars[:scope]
+		c.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		scope := len(c.vars)
+		t := c.pipe(n.Pipe, dot)
+		c.declare(n.Pipe, t)
+		c.walk(n.List, t)
+		c.vars = c.vars[:scope]
+		c.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		scope := len(c.vars)
+		t := c.pipe(n.Pipe, dot)
+		key, elem := rangeTypes(t)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			c.vars = append(c.vars, variable{name: n.Pipe.Decl[0].Ident[0], t: elem})
+		case 2:
+			c.vars = append(c.vars, variable{name: n.Pipe.Decl[0].Ident[0], t: key}, variable{name: n.Pipe.Decl[1].Ident[0], t: elem})
+		}
+		c.walk(n.List, elem)
+		c.vars = c.vars[:scope]
+		c.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		var t reflect.Type
+		if n.Pipe != nil {
+			t = c.pipe(n.Pipe, dot)
+		}
+		if tree := c.lookup(n.Name); tree != nil {
+			c.template(tree, t)
+		}
+	}
+}
+
+// declare declares or assigns the variables of p, which evaluated to t.
+func (c *checker) declare(p *parse.PipeNode, t reflect.Type) {
+	for _, v := range p.Decl {
+		name := v.Ident[0]
+		if !p.IsAssign {
+			c.vars = append(c.vars, variable{name: name, t: t})
+			continue
+		}
+		for i := len(c.vars) - 1; i >= 0; i-- {
+			if c.vars[i].name == name {
+				if c.vars[i].t != t {
+					// The variable could have either type
+					c.vars[i].t = nil
+				}
+				break
+			}
+		}
+	}
+}
+
+func (c *checker) variable(name string) reflect.Type {
+	for i := len(c.vars) - 1; i >= 0; i-- {
+		if c.vars[i].name == name {
+			return c.vars[i].t
+		}
+	}
+	return nil
+}
+
+// pipe returns the type p evaluates to, nil when it is not known.
+func (c *checker) pipe(p *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if p == nil {
+		return nil
+	}
+	var t reflect.Type
+	for i, cmd := range p.Cmds {
+		var piped []reflect.Type
+		if i > 0 {
+			piped = []reflect.Type{t}
+		}
+		t = c.command(cmd, dot, piped)
+	}
+	return t
+}
+
+// command returns the type cmd evaluates to. piped holds the type of the previous command of the pipeline, which is
+// passed as the last argument.
+func (c *checker) command(cmd *parse.CommandNode, dot reflect.Type, piped []reflect.Type) reflect.Type {
+	if id, ok := cmd.Args[0].(*parse.IdentifierNode); ok {
+		args := make([]reflect.Type, 0, len(cmd.Args))
+		for _, arg := range cmd.Args[1:] {
+			args = append(args, c.arg(arg, dot))
+		}
+		return c.call(cmd, id.Ident, append(args, piped...))
+	}
+	for _, arg := range cmd.Args[1:] {
+		c.arg(arg, dot)
+	}
+	return c.arg(cmd.Args[0], dot)
+}
+
+// arg returns the type of n, nil when it is not known.
+func (c *checker) arg(n parse.Node, dot reflect.Type) reflect.Type {
+	switch n := n.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return c.fields(n, dot, n.Ident)
+	case *parse.VariableNode:
+		return c.fields(n, c.variable(n.Ident[0]), n.Ident[1:])
+	case *parse.ChainNode:
+		return c.fields(n, c.arg(n.Node, dot), n.Field)
+	case *parse.PipeNode:
+		return c.pipe(n, dot)
+	case *parse.IdentifierNode:
+		return c.call(n, n.Ident, nil)
+	case *parse.StringNode:
+		return reflect.TypeFor[string]()
+	case *parse.BoolNode:
+		return boolType
+	case *parse.NumberNode:
+		switch {
+		case n.IsInt:
+			return reflect.TypeFor[int]()
+		case n.IsFloat:
+			return reflect.TypeFor[float64]()
+		}
+	}
+	return nil
+}
+
+// fields returns the type of the field chain names of a value of type t, reporting fields which do not exist.
+func (c *checker) fields(n parse.Node, t reflect.Type, names []string) reflect.Type {
+	for _, name := range names {
+		if t == nil || t.Kind() == reflect.Interface {
+			return nil
+		}
+		m, ok := t.MethodByName(name)
+		if !ok && t.Kind() != reflect.Pointer {
+			m, ok = reflect.PointerTo(t).MethodByName(name)
+		}
+		if ok {
+			if m.Type.NumOut() == 0 {
+				return nil
+			}
+			t = m.Type.Out(0)
+			continue
+		}
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := t.FieldByName(name)
+			if !ok || !f.IsExported() {
+				c.report(n, "", fmt.Errorf("%w: %s in %s", ErrFieldNotFound, name, t))
+				return nil
+			}
+			t = f.Type
+		case reflect.Map:
+			if t.Key().Kind() != reflect.String {
+				return nil
+			}
+			t = t.Elem()
+		case reflect.Interface:
+			return nil
+		default:
+			c.report(n, "", fmt.Errorf("%w: %s in %s", ErrFieldNotFound, name, t))
+			return nil
+		}
+	}
+	return t
+}
+
+// call checks a call of the function name and returns the type of its result.
+func (c *checker) call(n parse.Node, name string, args []reflect.Type) reflect.Type {
+	op, ok := c.ops[name]
+	if !ok {
+		switch name {
+		case "len":
+			return reflect.TypeFor[int]()
+		case "print", "printf", "println", "html", "js", "urlquery":
+			return reflect.TypeFor[string]()
+		case "eq", "ne", "lt", "le", "gt", "ge", "not":
+			return boolType
+		case "call":
+			if len(args) > 0 && args[0] != nil && args[0].Kind() == reflect.Func && args[0].NumOut() > 0 {
+				return args[0].Out(0)
+			}
+		}
+		return nil
+	}
+	predicate, checked := checkedOperations[op]
+	if !checked || len(args) != 2 {
+		return resultType(reflect.TypeOf(c.o.funcs(false)[op]))
+	}
+	p, elem, err := c.callback(args[0], args[1], predicate)
+	if err != nil {
+		c.report(n, op, err)
+		return nil
+	}
+	switch {
+	case p == nil:
+	case op == "map" || op == "pmap":
+		if !p.hasErr {
+			return reflect.SliceOf(p.out)
+		}
+	case op == "filter" || op == "pfilter":
+		if len(p.in) == 1 {
+			return reflect.SliceOf(p.in[0])
+		}
+		if elem != nil {
+			return reflect.SliceOf(elem)
+		}
+	}
+	return resultType(reflect.TypeOf(c.o.funcs(false)[op]))
+}
+
+// callback checks the callback of type f given with a slice of type slice, either of which may be unknown. It returns
+// the plan of the callback, if known, and the element type of the slice, if known.
+func (c *checker) callback(slice, f reflect.Type, predicate bool) (*plan, reflect.Type, error) {
+	var elem reflect.Type
+	if slice != nil {
+		switch {
+		case slice.Kind() == reflect.Slice || slice.Kind() == reflect.Array:
+			elem = slice.Elem()
+		case slice.Kind() == reflect.Func && slice.CanSeq() && slice.NumIn() == 1 && slice.In(0).NumIn() == 1:
+			elem = slice.In(0).In(0)
+		case slice.Kind() == reflect.Interface || slice == streamType:
+		default:
+			return nil, nil, fmt.Errorf("%w not %s", ErrExpectedFirstParameterToBeSlice, slice.Kind())
+		}
+	}
+	if f == nil || f.Kind() == reflect.Interface {
+		return nil, elem, nil
+	}
+	if f.Kind() != reflect.Func {
+		return nil, elem, ErrExpected2ndArgumentToBeFunction
+	}
+	p := newPlan(f, elem)
+	switch {
+	case len(p.in) > 1:
+		return nil, elem, ErrInputFuncMustTake0or1Arguments
+	case p.numOut != 1 && p.numOut != 2:
+		return nil, elem, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
+	case p.outErr != nil:
+		return nil, elem, p.outErr
+	case predicate && !p.returnsBool:
+		return nil, elem, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, p.out)
+	case elem != nil && len(p.in) == 1 && !p.elemInterface && !p.elemAssignable && !c.coercible(elem, p.in[0]):
+		return nil, elem, fmt.Errorf("elements of type %s %w: %s", elem, ErrItemNotAssignable, p.in[0])
+	}
+	return p, elem, nil
+}
+
+// coercible reports whether elements of type elem may be coerced to t, depending on their values.
+func (c *checker) coercible(elem, t reflect.Type) bool {
+	if c.o.coercion != CoercionNumeric {
+		return false
+	}
+	if _, err := numberOf(reflect.New(elem).Elem()); err != nil && elem != jsonNumberType {
+		return false
+	}
+	return orderClassOf(reflect.New(t).Elem()) != orderNone
+}
+
+var streamType = reflect.TypeFor[*Stream]()
+
+// resultType is the type of the result of a function of type ft, nil when it is only known when executing.
+func resultType(ft reflect.Type) reflect.Type {
+	if ft.NumOut() == 0 || ft.Out(0).Kind() == reflect.Interface {
+		return nil
+	}
+	return ft.Out(0)
+}
+
+// rangeTypes returns the types of the keys and elements ranging over a value of type t gives.
+func rangeTypes(t reflect.Type) (key, elem reflect.Type) {
+	if t == nil {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeFor[int](), t.Elem()
+	case reflect.Map:
+		return t.Key(), t.Elem()
+	case reflect.Chan:
+		return t.Elem(), t.Elem()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return t, t
+	case reflect.Func:
+		switch {
+		case t.CanSeq2():
+			return t.In(0).In(0), t.In(0).In(1)
+		case t.CanSeq():
+			return t.In(0).In(0), t.In(0).In(0)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,201 @@
+package funtemplates
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	ht "html/template"
+	"reflect"
+	"testing"
+	"text/template"
+)
+
+type checkUser struct {
+	Name   string
+	Age    int
+	Admin  bool
+	Scores []float64
+}
+
+func (u checkUser) Adult() bool { return u.Age >= 18 }
+
+type checkFuncs struct {
+	IsAdmin    func(u checkUser) bool
+	Name       func(u checkUser) string
+	Age        func(u *checkUser) int
+	Double     func(i int) int
+	Describe   func(u checkUser) (string, string)
+	Pair       func(a, b checkUser) bool
+	Positive   func(f float64) bool
+	Whole      func(i int) bool
+	Anything   func(v any) bool
+	Contextual func(ctx context.Context, u checkUser) bool
+}
+
+type checkData struct {
+	Users  []checkUser
+	Ptrs   []*checkUser
+	Groups map[string][]checkUser
+	Any    []any
+	Loose  map[string]any
+	F      checkFuncs
+	Count  int
+}
+
+func TestCheckText(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		template string
+		want     []string
+		wantErrs []error
+	}{
+		{
+			name:     "Valid",
+			template: "{{ map .Users .F.Name }} {{ filter .Users .F.IsAdmin }} {{ find .Users .F.IsAdmin }} {{ findIndex .Users .F.IsAdmin }}",
+		},
+		{
+			name:     "Predicate not returning bool",
+			template: "{{ filter .Users .F.Name }}",
+			want:     []string{"check:1:3: <filter .Users .F.Name>: filter: expected first return type to be assignable to bool instead got: string"},
+			wantErrs: []error{ErrExpectedFirstReturnToBeBool},
+		},
+		{
+			name:     "Elements not assignable",
+			template: "{{ map .Users .F.Double }}",
+			want:     []string{"check:1:3: <map .Users .F.Double>: map: elements of type funtemplates.checkUser not assignable to: int"},
+			wantErrs: []error{ErrItemNotAssignable},
+		},
+		{
+			name:     "Pointer elements not assignable",
+			template: "{{ map .Users .F.Age }}",
+			wantErrs: []error{ErrItemNotAssignable},
+		},
+		{
+			name:     "Too many parameters",
+			template: "{{ find .Users .F.Pair }}",
+			wantErrs: []error{ErrInputFuncMustTake0or1Arguments},
+		},
+		{
+			name:     "Second return not an error",
+			template: "{{ map .Users .F.Describe }}",
+			wantErrs: []error{ErrExpectedSecondReturnToBeError},
+		},
+		{
+			name:     "Not a function",
+			template: "{{ map .Users .Count }}",
+			wantErrs: []error{ErrExpected2ndArgumentToBeFunction},
+		},
+		{
+			name:     "Not a slice",
+			template: "{{ map .Count .F.Double }}",
+			wantErrs: []error{ErrExpectedFirstParameterToBeSlice},
+		},
+		{
+			name:     "Unknown field",
+			template: "{{ map .Users .F.Nme }}",
+			want:     []string{"check:1:16: <.F.Nme>: field not found: Nme in funtemplates.checkFuncs"},
+			wantErrs: []error{ErrFieldNotFound},
+		},
+		{
+			name:     "Range and with",
+			template: "{{ range .Groups }}{{ filter . $.F.Name }}{{ end }}\n{{ with .Users }}{{ map . $.F.Double }}{{ end }}",
+			wantErrs: []error{ErrExpectedFirstReturnToBeBool, ErrItemNotAssignable},
+		},
+		{
+			name:     "Range variables",
+			template: "{{ range $name, $users := .Groups }}{{ findIndex $users $.F.Whole }}{{ end }}",
+			wantErrs: []error{ErrItemNotAssignable},
+		},
+		{
+			name:     "Variables",
+			template: "{{ $u := .Users }}{{ $f := .F.Double }}{{ map $u $f }}",
+			wantErrs: []error{ErrItemNotAssignable},
+		},
+		{
+			name:     "Results of operations",
+			template: "{{ filter (map .Users .F.Name) .F.IsAdmin }} {{ filter (filter .Users .F.IsAdmin) .F.Whole }}",
+			wantErrs: []error{ErrItemNotAssignable, ErrItemNotAssignable},
+		},
+		{
+			name:     "Pipelines",
+			template: "{{ .Users | filter .F.Name }}",
+			wantErrs: []error{ErrExpectedFirstParameterToBeSlice},
+		},
+		{
+			name:     "Fields of elements",
+			template: "{{ range .Users }}{{ filter .Scores $.F.Whole }}{{ end }}",
+			wantErrs: []error{ErrItemNotAssignable},
+		},
+		{
+			name:     "Methods",
+			template: "{{ range .Users }}{{ if .Adult }}{{ .Nam }}{{ end }}{{ end }}",
+			wantErrs: []error{ErrFieldNotFound},
+		},
+		{
+			name:     "Invoked templates",
+			template: `{{ define "admins" }}{{ filter . $.F.Name }}{{ end }}{{ template "admins" .Users }}`,
+			wantErrs: []error{ErrFieldNotFound},
+		},
+		{
+			name:     "Invoked templates with their own dot",
+			template: `{{ define "user" }}{{ .Age.Years }}{{ end }}{{ range .Users }}{{ template "user" . }}{{ end }}`,
+			wantErrs: []error{ErrFieldNotFound},
+		},
+		{
+			name:     "Types only known when executing are not checked",
+			template: "{{ map .Any .F.Double }} {{ filter .Loose.Users .F.Whole }} {{ map .Users .Loose.F }} {{ filter .Users .F.Anything }}",
+		},
+		{
+			name:     "Context parameters",
+			template: "{{ filter .Users .F.Contextual }}",
+		},
+		{
+			name:     "Coercion",
+			opts:     []Option{WithCoercion(CoercionNumeric)},
+			template: "{{ range .Users }}{{ filter .Scores $.F.Whole }}{{ end }}",
+		},
+		{
+			name:     "Renamed operations",
+			opts:     []Option{WithPrefix("fp_")},
+			template: "{{ fp_filter .Users .F.Name }}",
+			wantErrs: []error{ErrExpectedFirstReturnToBeBool},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			funcs, err := New(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tmpl := template.Must(template.New("check").Funcs(funcs.Text()).Parse(tt.template))
+			diags := CheckText(tmpl, reflect.TypeFor[checkData](), tt.opts...)
+			if len(diags) != len(tt.wantErrs) {
+				t.Fatalf("expected %d diagnostics, got %v", len(tt.wantErrs), diags)
+			}
+			for i, d := range diags {
+				if !errors.Is(d, tt.wantErrs[i]) {
+					t.Errorf("diagnostic %d: expected %v, got %v", i, tt.wantErrs[i], d)
+				}
+			}
+			if tt.want != nil {
+				var got []string
+				for _, d := range diags {
+					got = append(got, d.Error())
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("diagnostics diff =\n %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckHtml(t *testing.T) {
+	tmpl := ht.Must(ht.New("check").Funcs(HtmlFunctions()).Parse(`<p>{{ join (filter .Users .F.Name) ", " }}</p>`))
+	diags := CheckHtml(tmpl, reflect.TypeFor[*checkData]())
+	if len(diags) != 1 || !errors.Is(diags[0], ErrExpectedFirstReturnToBeBool) {
+		t.Errorf("expected a diagnostic for filter, got %v", diags)
+	}
+}
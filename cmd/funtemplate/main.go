@@ -0,0 +1,175 @@
+// Command funtemplate renders templates with the functional operations from JSON data.
+//
+// Usage:
+//
+//	funtemplate [-data file.json] [-html] [-name template] [-o output] template...
+//
+// The data is read from stdin when -data is not given or is "-". The first template is executed unless -name
+// selects another, and the output is written to stdout unless -o is given, in which case the file is only written
+// when the template executes successfully.
+//
+// Whole JSON numbers are decoded as int and others as float64, and callbacks are given numbers converted to their
+// parameter types, see funtemplates.CoercionNumeric. The helpers of the misc package can be called directly, as in
+// {{ inc .count }}, and passed to the operations as callbacks with fn, as in {{ filter .ids (fn "odd") }}.
+//
+// It exits with 1 when the data can't be read or the output can't be written, 2 when the arguments are invalid and 3
+// when a template fails to parse or execute.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	funtemplates "github.com/arran4/go-template-functional-operations"
+	"github.com/arran4/go-template-functional-operations/misc"
+	ht "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	tt "text/template"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitFailure  = 1
+	exitUsage    = 2
+	exitTemplate = 3
+)
+
+var errUsage = errors.New("at least one template file is required")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with args, returning the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("funtemplate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dataFile := flags.String("data", "-", "JSON `file` to execute the templates with, - for stdin")
+	html := flags.Bool("html", false, "use html/template instead of text/template")
+	name := flags.String("name", "", "`name` of the template to execute, defaults to the first template file")
+	output := flags.String("o", "", "`file` to write the output to, defaults to stdout")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: funtemplate [flags] template...\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintf(stderr, "funtemplate: %v\n", errUsage)
+		flags.Usage()
+		return exitUsage
+	}
+	if *name == "" {
+		*name = filepath.Base(flags.Arg(0))
+	}
+
+	data, err := readData(*dataFile, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "funtemplate: %v\n", err)
+		return exitFailure
+	}
+
+	out := bytes.NewBuffer(nil)
+	if *html {
+		err = executeHtml(out, *name, flags.Args(), data)
+	} else {
+		err = executeText(out, *name, flags.Args(), data)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "funtemplate: %v\n", err)
+		return exitTemplate
+	}
+
+	if *output == "" {
+		_, err = out.WriteTo(stdout)
+	} else {
+		err = os.WriteFile(*output, out.Bytes(), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "funtemplate: writing output: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// readData decodes the JSON in file, or in stdin when file is "-".
+func readData(file string, stdin io.Reader) (any, error) {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading data: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	var data any
+	if err := d.Decode(&data); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decoding data from %s: %w", file, err)
+	}
+	return numbers(data), nil
+}
+
+// numbers replaces the json.Numbers in v with an int when they are whole and fit, otherwise a float64.
+func numbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := strconv.Atoi(v.String()); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i, e := range v {
+			v[i] = numbers(e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = numbers(e)
+		}
+	}
+	return v
+}
+
+// helpers returns the misc functions along with fn, which returns the one named so it can be passed as a callback.
+func helpers(funcs map[string]any) map[string]any {
+	return misc.MergeMaps(funcs, map[string]any{
+		"fn": func(name string) (any, error) {
+			f, ok := funcs[name]
+			if !ok {
+				return nil, fmt.Errorf("no helper function %q", name)
+			}
+			return f, nil
+		},
+	})
+}
+
+func executeText(w io.Writer, name string, files []string, data any) error {
+	funcs := funtemplates.TextFunctions(funtemplates.WithCoercion(funtemplates.CoercionNumeric))
+	t, err := tt.New(name).Funcs(funcs).Funcs(helpers(misc.SimpleTextFunctions())).ParseFiles(files...)
+	if err != nil {
+		return err
+	}
+	return t.ExecuteTemplate(w, name, data)
+}
+
+func executeHtml(w io.Writer, name string, files []string, data any) error {
+	funcs := funtemplates.HtmlFunctions(funtemplates.WithCoercion(funtemplates.CoercionNumeric))
+	t, err := ht.New(name).Funcs(funcs).Funcs(helpers(misc.SimpleHtmlFunctions())).ParseFiles(files...)
+	if err != nil {
+		return err
+	}
+	return t.ExecuteTemplate(w, name, data)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"text.tmpl":   `{{ join (seq 3) ", " }} {{ sum .xs }} {{ inc 1 }}`,
+		"html.tmpl":   `<p>{{ join (pluck .users "name") ", " }}</p>`,
+		"layout.tmpl": `[{{ template "text.tmpl" . }}]`,
+		"broken.tmpl": `{{ map .xs }`,
+		"fails.tmpl":  `{{ filter .xs .xs }}`,
+		"fn.tmpl":     `{{ map .xs (fn "inc") }} {{ filter .xs (fn "odd") }} {{ inc .x }}`,
+		"nofn.tmpl":   `{{ map .xs (fn "dec") }}`,
+		"data.json":   `{"xs": [1, 2, 3], "users": [{"name": "<b>"}, {"name": "c"}]}`,
+		"bad.json":    `{"xs": [`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		want       string
+		wantStderr string
+		code       int
+	}{
+		{
+			name:  "Text from stdin",
+			args:  []string{path("text.tmpl")},
+			stdin: `{"xs": [4, 5]}`,
+			want:  "0, 1, 2 9 2",
+		},
+		{
+			name: "Html from a data file",
+			args: []string{"-html", "-data", path("data.json"), path("html.tmpl")},
+			want: "<p>&lt;b&gt;, c</p>",
+		},
+		{
+			name:  "Callbacks",
+			args:  []string{path("fn.tmpl")},
+			stdin: `{"xs": [1, 2, 3], "x": 1}`,
+			want:  "[2 3 4] [1 3] 2",
+		},
+		{
+			name:  "Callbacks given whole floats",
+			args:  []string{path("fn.tmpl")},
+			stdin: `{"xs": [1.0, 2e0, 3], "x": 1}`,
+			want:  "[2 3 4] [1 3] 2",
+		},
+		{
+			name:       "Callbacks given fractions",
+			args:       []string{path("fn.tmpl")},
+			stdin:      `{"xs": [1.5], "x": 1}`,
+			wantStderr: "funtemplate: template: fn.tmpl:1:3: executing \"fn.tmpl\" at <map .xs (fn \"inc\")>",
+			code:       exitTemplate,
+		},
+		{
+			name:       "Unknown helper",
+			args:       []string{path("nofn.tmpl")},
+			stdin:      `{"xs": [1]}`,
+			wantStderr: `no helper function "dec"`,
+			code:       exitTemplate,
+		},
+		{
+			name: "Named template",
+			args: []string{"-data", path("data.json"), "-name", "layout.tmpl", path("text.tmpl"), path("layout.tmpl")},
+			want: "[0, 1, 2 6 2]",
+		},
+		{
+			name:       "No templates",
+			args:       []string{},
+			wantStderr: "funtemplate: at least one template file is required",
+			code:       exitUsage,
+		},
+		{
+			name:       "Unknown flag",
+			args:       []string{"-nope", path("text.tmpl")},
+			wantStderr: "flag provided but not defined: -nope",
+			code:       exitUsage,
+		},
+		{
+			name:       "Missing data file",
+			args:       []string{"-data", path("missing.json"), path("text.tmpl")},
+			wantStderr: "funtemplate: reading data:",
+			code:       exitFailure,
+		},
+		{
+			name:       "Invalid data",
+			args:       []string{"-data", path("bad.json"), path("text.tmpl")},
+			wantStderr: "funtemplate: decoding data from " + path("bad.json"),
+			code:       exitFailure,
+		},
+		{
+			name:       "Template fails to parse",
+			args:       []string{path("broken.tmpl")},
+			wantStderr: "funtemplate: template: broken.tmpl:1:",
+			code:       exitTemplate,
+		},
+		{
+			name:       "Template fails to execute",
+			args:       []string{"-data", path("data.json"), path("fails.tmpl")},
+			wantStderr: "funtemplate: template: fails.tmpl:1:3: executing \"fails.tmpl\" at <filter .xs .xs>",
+			code:       exitTemplate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+			code := run(tt.args, strings.NewReader(tt.stdin), stdout, stderr)
+			if code != tt.code {
+				t.Errorf("expected exit code %d, got %d: %s", tt.code, code, stderr)
+			}
+			if diff := cmp.Diff(tt.want, stdout.String()); diff != "" {
+				t.Errorf("output diff =\n %s", diff)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.wantStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRunWritesTheOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "text.tmpl")
+	if err := os.WriteFile(tmpl, []byte(`{{ sum .xs }}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "out.txt")
+	if code := run([]string{"-o", output, tmpl}, strings.NewReader(`{"xs": [1, 2]}`), nil, os.Stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d", exitOK, code)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("3", string(got)); diff != "" {
+		t.Errorf("output diff =\n %s", diff)
+	}
+
+	if code := run([]string{"-o", output, tmpl}, strings.NewReader(`{"xs": ["a"]}`), nil, bytes.NewBuffer(nil)); code != exitTemplate {
+		t.Fatalf("expected exit code %d, got %d", exitTemplate, code)
+	}
+	if got, _ := os.ReadFile(output); string(got) != "3" {
+		t.Errorf("expected the output file to be left alone on failure, got %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Coercion is how elements are passed to callbacks whose parameter they are not assignable to.
+type Coercion string
+
+const (
+	// CoercionStrict fails elements which are not assignable to the parameter. The default.
+	CoercionStrict Coercion = "strict"
+	// CoercionNumeric converts numbers, including those in interfaces and json.Number, to a numeric parameter when
+	// they fit, so a float64 of 2 from JSON can be passed to a func(int).
+	CoercionNumeric Coercion = "numeric"
+)
+
+// WithCoercion sets how elements are passed to callbacks whose parameter they are not assignable to.
+func WithCoercion(c Coercion) Option {
+	return func(o *operations) {
+		o.coercion = c
+	}
+}
+
+// check returns an error when c is not one of the coercions.
+func (c Coercion) check() error {
+	switch c {
+	case "", CoercionStrict, CoercionNumeric:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownCoercion, c)
+}
+
+// coerce returns v converted to t according to the coercion mode, or false when it cannot be.
+func (o *operations) coerce(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if o.coercion != CoercionNumeric {
+		return reflect.Value{}, false
+	}
+	n, err := numberOf(v)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	return n.convert(t)
+}
+
+// assign returns ev, element i, as a parameter of type t, coercing it when it is not assignable.
+func (o *operations) assign(i int, ev reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if ev.Type().AssignableTo(t) {
+		return ev, nil
+	}
+	if cv, ok := o.coerce(ev, t); ok {
+		return cv, nil
+	}
+	return reflect.Value{}, notAssignableError(i, ev, t)
+}
+
+// argument is argumentFor, coercing values which are not assignable to t.
+func (o *operations) argument(i int, v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	arg, err := argumentFor(i, v, t)
+	if err != nil && v.IsValid() {
+		if cv, ok := o.coerce(v, t); ok {
+			return cv, nil
+		}
+	}
+	return arg, err
+}
@@ -0,0 +1,136 @@
+package funtemplates
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestWithCoercion(t *testing.T) {
+	tests := []struct {
+		name       string
+		coercion   Coercion
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Strict by default",
+			template:   "{{ map $.Floats $.Inc }}",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "map of interfaces",
+			coercion:   CoercionNumeric,
+			template:   "{{ map $.Floats $.Inc }}",
+			want:       "[2 3 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "map of json.Number",
+			coercion:   CoercionNumeric,
+			template:   "{{ map $.Numbers $.Inc }}",
+			want:       "[8 9]",
+			correctErr: NoError,
+		},
+		{
+			name:       "map of a different numeric type",
+			coercion:   CoercionNumeric,
+			template:   "{{ map $.Int64s $.Inc }} {{ map $.Int64s $.Half }}",
+			want:       "[2 3] [0.5 1]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Fractions do not become integers",
+			coercion:   CoercionNumeric,
+			template:   "{{ map $.Fractions $.Inc }}",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "Overflowing values are not converted",
+			coercion:   CoercionNumeric,
+			template:   "{{ map $.Floats $.Byte }} {{ map $.Large $.Byte }}",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "Strings are not numbers",
+			coercion:   CoercionNumeric,
+			template:   "{{ map $.Strings $.Inc }}",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "filter keeps the coerced values",
+			coercion:   CoercionNumeric,
+			template:   "{{ printf \"%v %T\" (filter $.Floats $.Odd) (filter $.Int64s $.Odd) }}",
+			want:       "[1 3] []int",
+			correctErr: NoError,
+		},
+		{
+			name:       "find",
+			coercion:   CoercionNumeric,
+			template:   "{{ find $.Floats $.Even }} {{ findIndex $.Floats $.Even }}",
+			want:       "2 1",
+			correctErr: NoError,
+		},
+		{
+			name:       "pmap and pfilter",
+			coercion:   CoercionNumeric,
+			template:   "{{ pmap $.Floats $.Inc }} {{ pfilter $.Int64s $.Odd }}",
+			want:       "[2 3 4] [1]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Key functions",
+			coercion:   CoercionNumeric,
+			template:   "{{ sum $.Floats $.Inc }} {{ maxBy $.Floats $.Inc }}",
+			want:       "9 3",
+			correctErr: NoError,
+		},
+		{
+			name:       "Streams",
+			coercion:   CoercionNumeric,
+			template:   "{{ collect (lazyMap $.Floats $.Inc) }}",
+			want:       "[2 3 4]",
+			correctErr: NoError,
+		},
+	}
+	data := map[string]any{
+		"Floats":    []any{1.0, 2.0, 3.0},
+		"Numbers":   []json.Number{"7", "8.0"},
+		"Int64s":    []int64{1, 2},
+		"Fractions": []any{1.5},
+		"Large":     []any{300},
+		"Strings":   []any{"1"},
+		"Inc":       func(i int) int { return i + 1 },
+		"Half":      func(f float64) float64 { return f / 2 },
+		"Byte":      func(b uint8) uint8 { return b },
+		"Odd":       func(i int) bool { return i%2 == 1 },
+		"Even":      func(i int) bool { return i%2 == 0 },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.coercion != "" {
+				opts = append(opts, WithCoercion(tt.coercion))
+			}
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(opts...)).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("coercion got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("coercion diff =\n %s", diff)
+			}
+		})
+	}
+}
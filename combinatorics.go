@@ -0,0 +1,313 @@
+package funtemplates
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// maxTuples bounds the number of tuples the eager combinatoric operations will generate. The lazy variants are
+// not bounded as they never hold more than one tuple, but the operations copying them into a slice stop at
+// maxEagerSeq.
+const maxTuples = 100_000
+
+// tupleGenerator calls yield with the indexes making up each tuple, stopping early when yield returns false.
+type tupleGenerator func(yield func(indexes []int) bool)
+
+func CartesianProductTemplateFunc(slices ...any) (any, error) {
+	return defaultOperations.cartesianProduct(slices...)
+}
+
+func (o *operations) cartesianProduct(slices ...any) (any, error) {
+	g, err := o.newCartesianProduct(slices)
+	if err != nil {
+		return nil, opError("cartesianProduct", nil, err)
+	}
+	return g.collect(o, "cartesianProduct")
+}
+
+func CartesianProductIterTemplateFunc(slices ...any) (any, error) {
+	return defaultOperations.cartesianProductIter(slices...)
+}
+
+func (o *operations) cartesianProductIter(slices ...any) (any, error) {
+	g, err := o.newCartesianProduct(slices)
+	if err != nil {
+		return nil, opError("cartesianProductIter", nil, err)
+	}
+	return g.seq(), nil
+}
+
+func CombinationsTemplateFunc(slice any, k int) (any, error) {
+	return defaultOperations.combinations(slice, k)
+}
+
+func (o *operations) combinations(slice any, k int) (any, error) {
+	g, err := o.newCombinations(slice, k)
+	if err != nil {
+		return nil, opError("combinations", nil, err)
+	}
+	return g.collect(o, "combinations")
+}
+
+func CombinationsIterTemplateFunc(slice any, k int) (any, error) {
+	return defaultOperations.combinationsIter(slice, k)
+}
+
+func (o *operations) combinationsIter(slice any, k int) (any, error) {
+	g, err := o.newCombinations(slice, k)
+	if err != nil {
+		return nil, opError("combinationsIter", nil, err)
+	}
+	return g.seq(), nil
+}
+
+// PermutationsTemplateFunc returns every ordering of k elements of slice, or of all of them when k is omitted.
+func PermutationsTemplateFunc(slice any, k ...int) (any, error) {
+	return defaultOperations.permutations(slice, k...)
+}
+
+func (o *operations) permutations(slice any, k ...int) (any, error) {
+	g, err := o.newPermutations(slice, k)
+	if err != nil {
+		return nil, opError("permutations", nil, err)
+	}
+	return g.collect(o, "permutations")
+}
+
+func PermutationsIterTemplateFunc(slice any, k ...int) (any, error) {
+	return defaultOperations.permutationsIter(slice, k...)
+}
+
+func (o *operations) permutationsIter(slice any, k ...int) (any, error) {
+	g, err := o.newPermutations(slice, k)
+	if err != nil {
+		return nil, opError("permutationsIter", nil, err)
+	}
+	return g.seq(), nil
+}
+
+// tuples builds []T tuples, where source[i] supplies the value at position i of the tuple.
+type tuples struct {
+	source    []reflect.Value
+	tupleType reflect.Type
+	count     *big.Int
+	generate  tupleGenerator
+}
+
+func (o *operations) newCartesianProduct(slices []any) (*tuples, error) {
+	if len(slices) == 0 {
+		return nil, fmt.Errorf("%w got: 0", ErrExpectedAtLeastOneSlice)
+	}
+	avs := make([]reflect.Value, len(slices))
+	lengths := make([]int, len(slices))
+	count := big.NewInt(1)
+	for i, slice := range slices {
+		av, l, err := o.sliceArg(slice)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		avs[i], lengths[i] = av, l
+		count.Mul(count, big.NewInt(int64(l)))
+	}
+	elemType := sharedElemType(avs)
+	return &tuples{
+		source:    avs,
+		tupleType: reflect.SliceOf(elemType),
+		count:     count,
+		generate: func(yield func([]int) bool) {
+			indexes := make([]int, len(lengths))
+			for _, l := range lengths {
+				if l == 0 {
+					return
+				}
+			}
+			for {
+				if !yield(indexes) {
+					return
+				}
+				i := len(indexes) - 1
+				for ; i >= 0; i-- {
+					indexes[i]++
+					if indexes[i] < lengths[i] {
+						break
+					}
+					indexes[i] = 0
+				}
+				if i < 0 {
+					return
+				}
+			}
+		},
+	}, nil
+}
+
+func (o *operations) newCombinations(slice any, k int) (*tuples, error) {
+	av, n, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedNonNegativeCount, k)
+	}
+	count := new(big.Int)
+	if k <= n {
+		count.Binomial(int64(n), int64(k))
+	}
+	return &tuples{
+		source:    repeat(av, k),
+		tupleType: reflect.SliceOf(sharedElemType([]reflect.Value{av})),
+		count:     count,
+		generate: func(yield func([]int) bool) {
+			if k > n {
+				return
+			}
+			indexes := make([]int, k)
+			for i := range indexes {
+				indexes[i] = i
+			}
+			for {
+				if !yield(indexes) {
+					return
+				}
+				i := k - 1
+				for i >= 0 && indexes[i] == n-k+i {
+					i--
+				}
+				if i < 0 {
+					return
+				}
+				indexes[i]++
+				for j := i + 1; j < k; j++ {
+					indexes[j] = indexes[j-1] + 1
+				}
+			}
+		},
+	}, nil
+}
+
+func (o *operations) newPermutations(slice any, ks []int) (*tuples, error) {
+	av, n, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	k := n
+	switch len(ks) {
+	case 0:
+	case 1:
+		k = ks[0]
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedAtMostOneCount, len(ks))
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedNonNegativeCount, k)
+	}
+	count := new(big.Int)
+	if k <= n {
+		count.MulRange(int64(n-k+1), int64(n))
+	}
+	return &tuples{
+		source:    repeat(av, k),
+		tupleType: reflect.SliceOf(sharedElemType([]reflect.Value{av})),
+		count:     count,
+		generate: func(yield func([]int) bool) {
+			if k > n {
+				return
+			}
+			indexes := make([]int, 0, k)
+			used := make([]bool, n)
+			var permute func() bool
+			permute = func() bool {
+				if len(indexes) == k {
+					return yield(indexes)
+				}
+				for i := 0; i < n; i++ {
+					if used[i] {
+						continue
+					}
+					used[i] = true
+					indexes = append(indexes, i)
+					ok := permute()
+					indexes = indexes[:len(indexes)-1]
+					used[i] = false
+					if !ok {
+						return false
+					}
+				}
+				return true
+			}
+			permute()
+		},
+	}, nil
+}
+
+func (t *tuples) tuple(indexes []int) reflect.Value {
+	r := reflect.MakeSlice(t.tupleType, len(indexes), len(indexes))
+	for i, n := range indexes {
+		r.Index(i).Set(t.source[i].Index(n))
+	}
+	return r
+}
+
+func (t *tuples) collect(o *operations, op string) (any, error) {
+	if t.count.Cmp(big.NewInt(maxTuples)) > 0 {
+		return nil, opError(op, nil, fmt.Errorf("%w: %s tuples exceeds the limit of %d", ErrResultTooLarge, t.count, maxTuples))
+	}
+	l := int(t.count.Int64())
+	if err := o.checkResult(l); err != nil {
+		return nil, opError(op, nil, err)
+	}
+	r := reflect.MakeSlice(reflect.SliceOf(t.tupleType), 0, l)
+	var err error
+	t.generate(func(indexes []int) bool {
+		if err = o.checkContext(r.Len()); err != nil {
+			return false
+		}
+		r = reflect.Append(r, t.tuple(indexes))
+		return true
+	})
+	if err != nil {
+		return nil, opError(op, nil, err)
+	}
+	return r.Interface(), nil
+}
+
+// seq returns an iter.Seq[[]T] generating the tuples on demand.
+func (t *tuples) seq() any {
+	yieldType := reflect.FuncOf([]reflect.Type{t.tupleType}, []reflect.Type{boolType}, false)
+	seqType := reflect.FuncOf([]reflect.Type{yieldType}, nil, false)
+	return reflect.MakeFunc(seqType, func(args []reflect.Value) []reflect.Value {
+		yield := args[0]
+		t.generate(func(indexes []int) bool {
+			return yield.Call([]reflect.Value{t.tuple(indexes)})[0].Bool()
+		})
+		return nil
+	}).Interface()
+}
+
+func repeat(v reflect.Value, n int) []reflect.Value {
+	r := make([]reflect.Value, n)
+	for i := range r {
+		r[i] = v
+	}
+	return r
+}
+
+// sharedElemType is the element type of avs when they all agree, otherwise any.
+func sharedElemType(avs []reflect.Value) reflect.Type {
+	var t reflect.Type
+	for _, av := range avs {
+		if !av.IsValid() {
+			continue
+		}
+		if t == nil {
+			t = av.Type().Elem()
+		} else if t != av.Type().Elem() {
+			return anyType
+		}
+	}
+	if t == nil {
+		return anyType
+	}
+	return t
+}
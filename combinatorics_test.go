@@ -0,0 +1,176 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestCombinatoricsTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Cartesian product",
+			template:   "{{ cartesianProduct $.OS $.Arch }}",
+			want:       "[[linux amd64] [linux arm64] [darwin amd64] [darwin arm64]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Cartesian product of mixed types",
+			template:   `{{ printf "%T %v" (cartesianProduct $.OS $.Versions) (cartesianProduct $.OS $.Versions) }}`,
+			want:       "[][]interface {} [[linux 1] [linux 2] [darwin 1] [darwin 2]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Cartesian product with an empty slice",
+			template:   "{{ cartesianProduct $.OS nil }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Combinations",
+			template:   "{{ combinations $.Letters 2 }}",
+			want:       "[[a b] [a c] [a d] [b c] [b d] [c d]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Combinations of zero",
+			template:   "{{ combinations $.Letters 0 }}",
+			want:       "[[]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Combinations larger than the slice",
+			template:   "{{ combinations $.Letters 5 }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Permutations",
+			template:   "{{ permutations $.Arch }}",
+			want:       "[[amd64 arm64] [arm64 amd64]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Permutations of k",
+			template:   "{{ permutations $.Letters 2 }}",
+			want:       "[[a b] [a c] [a d] [b a] [b c] [b d] [c a] [c b] [c d] [d a] [d b] [d c]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Tuples are typed",
+			template:   `{{ printf "%T" (combinations $.Letters 2) }}`,
+			want:       "[][]string",
+			correctErr: NoError,
+		},
+		{
+			name:       "Iterators",
+			template:   "{{ range cartesianProductIter $.OS $.Arch }}{{ index . 0 }}/{{ index . 1 }} {{ end }}",
+			want:       "linux/amd64 linux/arm64 darwin/amd64 darwin/arm64 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Iterators stop early",
+			template:   "{{ range permutationsIter $.Large }}{{ . }}{{ break }}{{ end }}",
+			want:       "[0 1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Iterators compose with map",
+			template:   "{{ map (combinationsIter $.Letters 3) $.Funcs.concat }}",
+			want:       "[abc abd acd bcd]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Explosive results are refused",
+			template:   "{{ permutations $.Large }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Explosive iterators are refused when copied",
+			template:   "{{ map (permutationsIter $.Large) $.Funcs.first }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Explosive iterators can be taken from",
+			template:   "{{ take (lazyMap (permutationsIter $.Large) $.Funcs.first) 3 }}",
+			want:       "[0 0 0]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Negative count",
+			template:   "{{ combinations $.Letters -1 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedNonNegativeCount),
+		},
+		{
+			name:       "Cartesian product needs a slice",
+			template:   "{{ cartesianProduct }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedAtLeastOneSlice),
+		},
+		{
+			name:       "Arguments must be slices",
+			template:   "{{ cartesianProduct $.OS 1 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	large := make([]int, 20)
+	for i := range large {
+		large[i] = i
+	}
+	data := struct {
+		OS       []string
+		Arch     []string
+		Versions []int
+		Letters  []string
+		Large    []int
+		Funcs    map[string]any
+	}{
+		OS:       []string{"linux", "darwin"},
+		Arch:     []string{"amd64", "arm64"},
+		Versions: []int{1, 2},
+		Letters:  []string{"a", "b", "c", "d"},
+		Large:    large,
+		Funcs: map[string]any{
+			"first": func(s []int) int { return s[0] },
+			"concat": func(s []string) string {
+				r := ""
+				for _, e := range s {
+					r += e
+				}
+				return r
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("CombinationsTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("CombinationsTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package funtemplates
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type orderClass int
+
+const (
+	orderNone orderClass = iota
+	orderInt
+	orderUint
+	orderFloat
+	orderString
+	orderTime
+)
+
+func orderClassOf(v reflect.Value) orderClass {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return orderInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return orderUint
+	case reflect.Float32, reflect.Float64:
+		return orderFloat
+	case reflect.String:
+		return orderString
+	case reflect.Struct:
+		if v.Type().ConvertibleTo(timeType) {
+			return orderTime
+		}
+	}
+	return orderNone
+}
+
+// compareValues orders a and b. Numbers of any kind compare with each other by value, strings compare with strings
+// and times compare with times; any other combination is an ErrIncomparableTypes.
+func compareValues(a, b reflect.Value) (int, error) {
+	a, b = indirectInterface(a), indirectInterface(b)
+	ac, bc := orderNone, orderNone
+	if a.IsValid() {
+		ac = orderClassOf(a)
+	}
+	if b.IsValid() {
+		bc = orderClassOf(b)
+	}
+	if ac == orderNone || bc == orderNone {
+		return 0, fmt.Errorf("%w: %s and %s", ErrIncomparableTypes, typeName(a), typeName(b))
+	}
+	switch {
+	case ac == orderString && bc == orderString:
+		return cmp.Compare(a.String(), b.String()), nil
+	case ac == orderTime && bc == orderTime:
+		return a.Convert(timeType).Interface().(time.Time).Compare(b.Convert(timeType).Interface().(time.Time)), nil
+	case ac == orderString || bc == orderString || ac == orderTime || bc == orderTime:
+		return 0, fmt.Errorf("%w: %s and %s", ErrIncomparableTypes, typeName(a), typeName(b))
+	case ac == orderInt && bc == orderInt:
+		return cmp.Compare(a.Int(), b.Int()), nil
+	case ac == orderUint && bc == orderUint:
+		return cmp.Compare(a.Uint(), b.Uint()), nil
+	case ac == orderInt && bc == orderUint:
+		if a.Int() < 0 {
+			return -1, nil
+		}
+		return cmp.Compare(uint64(a.Int()), b.Uint()), nil
+	case ac == orderUint && bc == orderInt:
+		if b.Int() < 0 {
+			return 1, nil
+		}
+		return cmp.Compare(a.Uint(), uint64(b.Int())), nil
+	}
+	return cmp.Compare(floatOf(a), floatOf(b)), nil
+}
+
+func floatOf(v reflect.Value) float64 {
+	switch orderClassOf(v) {
+	case orderInt:
+		return float64(v.Int())
+	case orderUint:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+func indirectInterface(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func typeName(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return v.Type().String()
+}
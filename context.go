@@ -0,0 +1,48 @@
+package funtemplates
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// WithContext binds ctx to the functions. Callbacks whose first parameter is a context.Context are passed it, and
+// every operation stops between elements once it is done, returning an error wrapping ctx.Err(). To use a context
+// per execution, such as a request's, add the functions to a clone of the template:
+//
+//	t, _ := tmpl.Clone()
+//	t.Funcs(funtemplates.TextFunctions(funtemplates.WithContext(r.Context())))
+func WithContext(ctx context.Context) Option {
+	return func(o *operations) {
+		o.ctx = ctx
+	}
+}
+
+// context returns the bound context, or context.Background.
+func (o *operations) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+// checkContext is called before element i, returning an error once the bound context is done.
+func (o *operations) checkContext(i int) error {
+	if o.ctx == nil {
+		return nil
+	}
+	if err := o.ctx.Err(); err != nil {
+		return fmt.Errorf("stopped before item %d: %w", i, err)
+	}
+	return nil
+}
+
+// checkContextEvery is checkContext for loops doing very little per element, only checking every 1024 elements.
+func (o *operations) checkContextEvery(i int) error {
+	if i%1024 != 0 {
+		return nil
+	}
+	return o.checkContext(i)
+}
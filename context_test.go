@@ -0,0 +1,180 @@
+package funtemplates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+type ctxKey struct{}
+
+func TestWithContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name       string
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Callbacks are passed the context",
+			opts:       []Option{WithContext(context.WithValue(context.Background(), ctxKey{}, "req-1"))},
+			template:   "{{ map $.Data $.Tag }} {{ filter $.Data $.Has }} {{ pmap $.Data $.Tag }} {{ collect (lazyMap $.Data $.Tag) }}",
+			want:       "[req-1:1 req-1:2 req-1:3] [1 2 3] [req-1:1 req-1:2 req-1:3] [req-1:1 req-1:2 req-1:3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Callbacks are passed context.Background without a context",
+			template:   "{{ map $.Data $.Tag }}",
+			want:       "[<nil>:1 <nil>:2 <nil>:3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Context callbacks without parameters",
+			opts:       []Option{WithContext(context.WithValue(context.Background(), ctxKey{}, "req-1"))},
+			template:   "{{ map $.Data $.Value }}",
+			want:       "[req-1 req-1 req-1]",
+			correctErr: NoError,
+		},
+		{
+			name:       "map stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ map $.Data $.Tag }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "map fast path stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ map $.Data $.Double }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "filter stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ filter $.Data $.Has }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "findIndex stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ findIndex $.Data $.Has }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "pmap stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ pmap $.Data $.Tag }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "collect stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ collect (lazyMap $.Data $.Tag) }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "sum stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ sum $.Data }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "seq stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ seq 10 }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "combinations stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ combinations $.Data 2 }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "union stops",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ union $.Data $.Data }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "Iterators stop",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ map (seqIter 1000000000000) $.Double }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "Cancellation is not an element error",
+			opts:       []Option{WithContext(cancelled), WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ map $.Data $.Tag }}",
+			correctErr: ErrorIs(context.Canceled),
+		},
+		{
+			name:       "Empty slices do not check the context",
+			opts:       []Option{WithContext(cancelled)},
+			template:   "{{ map $.Empty $.Tag }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+	}
+	data := map[string]any{
+		"Data":  []int{1, 2, 3},
+		"Empty": []int{},
+		"Tag": func(ctx context.Context, i int) string {
+			return fmt.Sprintf("%v:%d", ctx.Value(ctxKey{}), i)
+		},
+		"Value": func(ctx context.Context) any {
+			return ctx.Value(ctxKey{})
+		},
+		"Has": func(ctx context.Context, i int) bool {
+			return ctx != nil
+		},
+		"Double": func(i int) int { return i * 2 },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(tt.opts...)).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("context got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("context diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestCancellationStopsBetweenElements(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	mapFunc := TextFunctions(WithContext(ctx))["map"].(func(any, any) (any, error))
+	_, err := mapFunc(make([]int, 10), func(i int) int {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return i
+	})
+	var oe *OpError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &oe) || oe.Op != "map" {
+		t.Errorf("expected map to stop with context.Canceled, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected f to be called 3 times, got %d", calls)
+	}
+}
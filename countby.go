@@ -0,0 +1,118 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type CountOrder string
+
+const (
+	CountOrderFirstSeen CountOrder = "first"
+	CountOrderSorted    CountOrder = "sorted"
+	CountOrderCount     CountOrder = "count"
+)
+
+type CountEntry struct {
+	Key   any
+	Count int
+}
+
+// CountByTemplateFunc counts the elements of slice per result of f. Entries are in the order the key was first seen
+// unless order is CountOrderSorted, ascending by key, or CountOrderCount, descending by count.
+func CountByTemplateFunc(slice any, f any, order ...CountOrder) ([]CountEntry, error) {
+	return defaultOperations.countBy(slice, f, order...)
+}
+
+func TallyTemplateFunc(slice any, order ...CountOrder) ([]CountEntry, error) {
+	return defaultOperations.tally(slice, order...)
+}
+
+func (o *operations) countBy(slice any, f any, order ...CountOrder) ([]CountEntry, error) {
+	c, err := o.newCallback(f, 1)
+	if err == nil {
+		var r []CountEntry
+		if r, err = o.count(slice, c, order); err == nil {
+			return r, nil
+		}
+	}
+	return nil, opError("countBy", f, err)
+}
+
+func (o *operations) tally(slice any, order ...CountOrder) ([]CountEntry, error) {
+	r, err := o.count(slice, nil, order)
+	if err != nil {
+		return nil, opError("tally", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) count(slice any, c *callback, order []CountOrder) ([]CountEntry, error) {
+	ord := CountOrderFirstSeen
+	switch len(order) {
+	case 0:
+	case 1:
+		ord = order[0]
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedAtMostOnePolicy, len(order))
+	}
+	switch ord {
+	case CountOrderFirstSeen, CountOrderSorted, CountOrderCount:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPolicy, ord)
+	}
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	keys := newKeySet()
+	var keyValues []reflect.Value
+	entries := []CountEntry{}
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		key := av.Index(i)
+		if c != nil {
+			if key, err = c.call(i, key); err != nil {
+				return nil, err
+			}
+		}
+		id, isNew := keys.id(key)
+		if isNew {
+			keyValues = append(keyValues, key)
+			entries = append(entries, CountEntry{Key: key.Interface()})
+		}
+		entries[id].Count++
+	}
+
+	switch ord {
+	case CountOrderSorted:
+		index := make([]int, len(entries))
+		for i := range index {
+			index[i] = i
+		}
+		sort.SliceStable(index, func(i, j int) bool {
+			if err != nil {
+				return false
+			}
+			var r int
+			r, err = compareValues(keyValues[index[i]], keyValues[index[j]])
+			return r < 0
+		})
+		if err != nil {
+			return nil, err
+		}
+		sorted := make([]CountEntry, len(entries))
+		for i, n := range index {
+			sorted[i] = entries[n]
+		}
+		entries = sorted
+	case CountOrderCount:
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Count > entries[j].Count
+		})
+	}
+	return entries, nil
+}
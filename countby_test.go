@@ -0,0 +1,126 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+type post struct {
+	Title  string
+	Status string
+	Tags   []string
+}
+
+func TestCountByTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Count by status in first seen order",
+			template:   "{{ range countBy $.Posts $.Funcs.status }}{{ .Key }}={{ .Count }} {{ end }}",
+			want:       "published=2 draft=1 archived=1 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Count by status sorted by key",
+			template:   `{{ range countBy $.Posts $.Funcs.status "sorted" }}{{ .Key }}={{ .Count }} {{ end }}`,
+			want:       "archived=1 draft=1 published=2 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Count by status sorted by count",
+			template:   `{{ range countBy $.Posts $.Funcs.status "count" }}{{ .Key }}={{ .Count }} {{ end }}`,
+			want:       "published=2 draft=1 archived=1 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Tally",
+			template:   "{{ range tally $.Tags }}{{ .Key }}={{ .Count }} {{ end }}",
+			want:       "go=3 templates=1 reflection=1 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Tally sorted",
+			template:   `{{ range tally $.Numbers "sorted" }}{{ .Key }}={{ .Count }} {{ end }}`,
+			want:       "1=1 2=2 3=1 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Tally of non comparable elements",
+			template:   "{{ range tally $.Lists }}{{ .Key }}={{ .Count }} {{ end }}",
+			want:       "[1]=2 [2]=1 ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Tally of nil",
+			template:   "{{ tally nil }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Sorting keys which cannot be ordered",
+			template:   `{{ tally $.Lists "sorted" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrIncomparableTypes),
+		},
+		{
+			name:       "Unknown order",
+			template:   `{{ tally $.Numbers "random" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrUnknownPolicy),
+		},
+		{
+			name:       "Correct error on not a func",
+			template:   "{{ countBy $.Posts $.Tags }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		Posts   []post
+		Tags    []string
+		Numbers []int
+		Lists   [][]int
+		Funcs   map[string]any
+	}{
+		Posts: []post{
+			{Title: "a", Status: "published"},
+			{Title: "b", Status: "draft"},
+			{Title: "c", Status: "archived"},
+			{Title: "d", Status: "published"},
+		},
+		Tags:    []string{"go", "templates", "go", "reflection", "go"},
+		Numbers: []int{3, 2, 1, 2},
+		Lists:   [][]int{{1}, {2}, {1}},
+		Funcs: map[string]any{
+			"status": func(p post) string { return p.Status },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("CountByTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("CountByTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
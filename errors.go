@@ -1,6 +1,10 @@
 package funtemplates
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
 
 var (
 	ErrInputFuncMustTake0or1Arguments  = errors.New("expected second parameter function to take 0 or 1 parameters")
@@ -9,4 +13,91 @@ var (
 	ErrExpectedSecondReturnToBeError   = errors.New("expected second return type to be assignable to error")
 	ErrExpected1Or2ReturnTypes         = errors.New("expected return with 1 or 2 arguments of types (any, error?)")
 	ErrExpectedFirstReturnToBeBool     = errors.New("expected first return type to be assignable to bool")
+	ErrExpectedAtMostOneDefault        = errors.New("expected at most one default value")
+	ErrInputFuncTooManyArguments       = errors.New("expected function to take fewer parameters")
+	ErrEmptySlice                      = errors.New("expected a non-empty slice")
+	ErrIncomparableTypes               = errors.New("values cannot be ordered")
+	ErrExpectedAtMostOneCallback       = errors.New("expected at most one function")
+	ErrNotANumber                      = errors.New("expected a number")
+	ErrIntegerOverflow                 = errors.New("integer overflow")
+	ErrExpectedAtLeastTwoSlices        = errors.New("expected at least two slices")
+	ErrIncompatibleElementTypes        = errors.New("slices have incompatible element types")
+	ErrExpectedAtMostOnePolicy         = errors.New("expected at most one policy")
+	ErrUnknownPolicy                   = errors.New("unknown policy")
+	ErrKeyNotComparable                = errors.New("key type is not comparable")
+	ErrDuplicateKey                    = errors.New("duplicate key")
+	ErrExpected1To3Arguments           = errors.New("expected 1 to 3 arguments")
+	ErrZeroStep                        = errors.New("step must not be zero")
+	ErrResultTooLarge                  = errors.New("result too large")
+	ErrExpectedSeedAndFunction         = errors.New("expected a function optionally preceded by a seed")
+	ErrInputFuncMustTake2Arguments     = errors.New("expected function to take 2 parameters")
+	ErrExpectedAccumulatorReturn       = errors.New("expected first return type to be assignable to the accumulator")
+	ErrExpectedAtMostOneSeparator      = errors.New("expected at most one last separator")
+	ErrExpectedFieldPath               = errors.New("expected at least one field path")
+	ErrFieldNotFound                   = errors.New("field not found")
+	ErrNilInPath                       = errors.New("nil value in field path")
+	ErrExpectedAtLeastOneSlice         = errors.New("expected at least one slice")
+	ErrExpectedNonNegativeCount        = errors.New("expected a count of 0 or more")
+	ErrExpectedAtMostOneCount          = errors.New("expected at most one count")
+	ErrExpectedSliceOfSlices           = errors.New("expected each element to be a slice")
+	ErrRaggedRows                      = errors.New("rows have different lengths")
+	ErrItemNotAssignable               = errors.New("not assignable to")
+	ErrCallbackReturnedError           = errors.New("f returned an error")
+	ErrSubstituteNotAssignable         = errors.New("substitute not assignable")
+	ErrExpectedPolicyAndSubstitute     = errors.New("expected at most a policy and a substitute")
+	ErrCallbackPanicked                = errors.New("f panicked")
+	ErrBudgetExceeded                  = errors.New("budget exceeded")
+	ErrUnknownOperation                = errors.New("unknown operation")
+	ErrInvalidFunctionName             = errors.New("invalid function name")
+	ErrDuplicateFunctionName           = errors.New("duplicate function name")
+	ErrUnknownCoercion                 = errors.New("unknown coercion")
+	ErrNotForExecution                 = errors.New("not the functions of an execution")
 )
+
+// OpError is the error returned by every operation. Index and Element identify the element being processed when it
+// failed, Index is -1 when the error is not about a single element. It unwraps to Cause, which in turn wraps one of
+// the errors above and, for ErrCallbackReturnedError, the error returned by the callback.
+type OpError struct {
+	Op           string
+	Index        int
+	Element      any
+	CallbackType reflect.Type
+	Cause        error
+}
+
+func (e *OpError) Error() string {
+	if e.Index < 0 {
+		return e.Op + ": " + e.Cause.Error()
+	}
+	return fmt.Sprintf("%s: item %d: %s", e.Op, e.Index, e.Cause)
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Cause
+}
+
+func elementError(i int, element reflect.Value, cause error) error {
+	return &OpError{Index: i, Element: indirectInterfaceValue(element), Cause: cause}
+}
+
+func notAssignableError(i int, element reflect.Value, t reflect.Type) error {
+	return elementError(i, element, fmt.Errorf("%w: %s", ErrItemNotAssignable, t))
+}
+
+func callbackError(i int, element reflect.Value, err error) error {
+	return elementError(i, element, fmt.Errorf("%w: %w", ErrCallbackReturnedError, err))
+}
+
+// opError names the operation, and callback if any, an error came from. Errors which are not already an element
+// OpError are wrapped in one with an Index of -1.
+func opError(op string, f any, err error) error {
+	oe, ok := err.(*OpError)
+	if !ok || oe.Op != "" {
+		oe = &OpError{Index: -1, Cause: err}
+	}
+	oe.Op = op
+	if fv := reflect.ValueOf(f); fv.Kind() == reflect.Func {
+		oe.CallbackType = fv.Type()
+	}
+	return oe
+}
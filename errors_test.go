@@ -0,0 +1,119 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"text/template"
+)
+
+type validationError struct {
+	Field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.Field
+}
+
+func TestOpError(t *testing.T) {
+	failOnThree := func(i int) (int, error) {
+		if i == 3 {
+			return 0, &validationError{Field: "three"}
+		}
+		return i, nil
+	}
+	tests := []struct {
+		name         string
+		call         func() error
+		wantOp       string
+		wantIndex    int
+		wantElement  any
+		wantCallback reflect.Type
+		wantIs       error
+	}{
+		{
+			name:         "Callback error",
+			call:         func() error { _, err := MapTemplateFunc([]int{1, 2, 3}, failOnThree); return err },
+			wantOp:       "map",
+			wantIndex:    2,
+			wantElement:  3,
+			wantCallback: reflect.TypeOf(failOnThree),
+			wantIs:       ErrCallbackReturnedError,
+		},
+		{
+			name: "Element not assignable",
+			call: func() error {
+				_, err := FilterTemplateFunc([]any{1, "two"}, func(i int) bool { return true })
+				return err
+			},
+			wantOp:       "filter",
+			wantIndex:    1,
+			wantElement:  "two",
+			wantCallback: reflect.TypeOf(func(i int) bool { return true }),
+			wantIs:       ErrItemNotAssignable,
+		},
+		{
+			name:         "Signature error",
+			call:         func() error { _, err := FindIndexTemplateFunc([]int{1}, func(i int) int { return i }); return err },
+			wantOp:       "findIndex",
+			wantIndex:    -1,
+			wantCallback: reflect.TypeOf(func(i int) int { return i }),
+			wantIs:       ErrExpectedFirstReturnToBeBool,
+		},
+		{
+			name:      "Not a slice",
+			call:      func() error { _, err := SumTemplateFunc(1); return err },
+			wantOp:    "sum",
+			wantIndex: -1,
+			wantIs:    ErrExpectedFirstParameterToBeSlice,
+		},
+		{
+			name:        "Element error without a callback",
+			call:        func() error { _, err := MaxTemplateFunc([]any{1, "one"}); return err },
+			wantOp:      "max",
+			wantIndex:   1,
+			wantElement: "one",
+			wantIs:      ErrIncomparableTypes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var oe *OpError
+			if !errors.As(err, &oe) {
+				t.Fatalf("expected an *OpError, got %T: %v", err, err)
+			}
+			if oe.Op != tt.wantOp || oe.Index != tt.wantIndex || oe.Element != tt.wantElement || oe.CallbackType != tt.wantCallback {
+				t.Errorf("got %s %d %v %v, want %s %d %v %v", oe.Op, oe.Index, oe.Element, oe.CallbackType, tt.wantOp, tt.wantIndex, tt.wantElement, tt.wantCallback)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected errors.Is(%v, %v)", err, tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestOpErrorWrapsCallbackError(t *testing.T) {
+	_, err := MapTemplateFunc([]string{"a"}, func(s string) (string, error) {
+		return "", &validationError{Field: s}
+	})
+	var ve *validationError
+	if !errors.As(err, &ve) || ve.Field != "a" {
+		t.Fatalf("expected errors.As to find the callback's error, got %v", err)
+	}
+	if got, want := err.Error(), "map: item 0: f returned an error: invalid a"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// The error survives being returned through a template.
+	tmpl := template.Must(template.New("").Funcs(TextFunctions()).Parse("{{ map .Data .Fail }}"))
+	err = tmpl.Execute(&bytes.Buffer{}, map[string]any{
+		"Data": []int{1},
+		"Fail": func(i int) (int, error) { return 0, &validationError{Field: "tmpl"} },
+	})
+	var oe *OpError
+	if !errors.As(err, &oe) || !errors.As(err, &ve) || ve.Field != "tmpl" {
+		t.Fatalf("expected the template error to wrap the OpError and callback error, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package funtemplates
+
+import (
+	"reflect"
+)
+
+// The fast paths run map, filter and findIndex through the generic core, without reflection, for the most common
+// slice and callback types. They give the same results as the reflection based implementations.
+
+func (o *operations) fastMap(slice any, f any, ee *elementErrors) (any, bool, error) {
+	if o.callbackObserver != nil {
+		return nil, false, nil
+	}
+	switch s := slice.(type) {
+	case []int:
+		return fastMapOf(o, s, f, ee)
+	case []string:
+		return fastMapOf(o, s, f, ee)
+	case []any:
+		return fastMapOf(o, s, f, ee)
+	case []map[string]any:
+		return fastMapOf(o, s, f, ee)
+	}
+	return nil, false, nil
+}
+
+func fastMapOf[T any](o *operations, s []T, f any, ee *elementErrors) (any, bool, error) {
+	switch f := f.(type) {
+	case func(T) T:
+		return mapped(mapEach(o, s, guarded(o, noErr(f)), ee))
+	case func(T) bool:
+		return mapped(mapEach(o, s, guarded(o, noErr(f)), ee))
+	case func(T) int:
+		return mapped(mapEach(o, s, guarded(o, noErr(f)), ee))
+	case func(T) string:
+		return mapped(mapEach(o, s, guarded(o, noErr(f)), ee))
+	case func(T) any:
+		return mapped(mapEach(o, s, guarded(o, noErr(f)), ee))
+	case func(T) (T, error):
+		if reflect.TypeFor[T]().Kind() == reflect.Interface {
+			// The result type is inferred from the dynamic values returned
+			return nil, false, nil
+		}
+		return mappedErr(mapEach(o, s, guarded(o, withIndex(f)), ee))
+	case func(T) (bool, error):
+		return mappedErr(mapEach(o, s, guarded(o, withIndex(f)), ee))
+	case func(T) (int, error):
+		return mappedErr(mapEach(o, s, guarded(o, withIndex(f)), ee))
+	case func(T) (string, error):
+		return mappedErr(mapEach(o, s, guarded(o, withIndex(f)), ee))
+	}
+	return nil, false, nil
+}
+
+func mapped[U any](r []U, err error) (any, bool, error) {
+	if err != nil {
+		return nil, true, err
+	}
+	return r, true, nil
+}
+
+// mappedErr is mapped for functions which can fail, whose results are []any when there are none, as their type is
+// inferred from the values returned.
+func mappedErr[U any](r []U, err error) (any, bool, error) {
+	if err == nil && len(r) == 0 {
+		return []any{}, true, nil
+	}
+	return mapped(r, err)
+}
+
+func (o *operations) fastFilter(slice any, f any, ee *elementErrors) (any, bool, error) {
+	if o.callbackObserver != nil {
+		return nil, false, nil
+	}
+	switch s := slice.(type) {
+	case []int:
+		return fastFilterOf(o, s, f, ee)
+	case []string:
+		return fastFilterOf(o, s, f, ee)
+	case []any:
+		return fastFilterOf(o, s, f, ee)
+	case []map[string]any:
+		return fastFilterOf(o, s, f, ee)
+	}
+	return nil, false, nil
+}
+
+func fastFilterOf[T any](o *operations, s []T, f any, ee *elementErrors) (any, bool, error) {
+	var r []T
+	var err error
+	switch f := f.(type) {
+	case func(T) bool:
+		r, err = filterEach(o, s, guarded(o, noErr(f)), ee)
+	case func(T) (bool, error):
+		r, err = filterEach(o, s, guarded(o, withIndex(f)), ee)
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return r, true, nil
+}
+
+func (o *operations) fastFindIndex(slice any, f any) (int, bool, error) {
+	if o.callbackObserver != nil {
+		return -1, false, nil
+	}
+	switch s := slice.(type) {
+	case []int:
+		return fastFindIndexOf(o, s, f)
+	case []string:
+		return fastFindIndexOf(o, s, f)
+	case []any:
+		return fastFindIndexOf(o, s, f)
+	case []map[string]any:
+		return fastFindIndexOf(o, s, f)
+	}
+	return -1, false, nil
+}
+
+func fastFindIndexOf[T any](o *operations, s []T, f any) (int, bool, error) {
+	var i int
+	var err error
+	switch f := f.(type) {
+	case func(T) bool:
+		i, err = findIndexEach(o, s, guarded(o, noErr(f)))
+	case func(T) (bool, error):
+		i, err = findIndexEach(o, s, guarded(o, withIndex(f)))
+	default:
+		return -1, false, nil
+	}
+	return i, true, err
+}
+
+// guarded turns panics in f into element errors, like call does for reflected functions.
+func guarded[T, U any](o *operations, f func(int, T) (U, error)) func(int, T) (U, error) {
+	return func(i int, v T) (u U, err error) {
+		if err := o.countCall(); err != nil {
+			return u, err
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				err = o.panicked(i, valueOf(v), p)
+			}
+		}()
+		return f(i, v)
+	}
+}
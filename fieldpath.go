@@ -0,0 +1,50 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// resolvePath follows a dot separated path of struct fields, including promoted fields of embedded structs, and
+// map keys through any pointers or interfaces along the way. A missing map key at the end of the path resolves to
+// the invalid Value, as it prints as no value in templates.
+func resolvePath(v reflect.Value, path string) (reflect.Value, error) {
+	segments := strings.Split(path, ".")
+	for n, seg := range segments {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("%w: nil %s evaluating %q", ErrNilInPath, v.Type(), strings.Join(segments[:n+1], "."))
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			sf, ok := v.Type().FieldByName(seg)
+			if !ok || !sf.IsExported() {
+				return reflect.Value{}, fmt.Errorf("%w: %q in %s", ErrFieldNotFound, seg, v.Type())
+			}
+			fv, err := v.FieldByIndexErr(sf.Index)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%w: evaluating %q: %s", ErrNilInPath, strings.Join(segments[:n+1], "."), err)
+			}
+			v = fv
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, fmt.Errorf("%w: %q in %s", ErrFieldNotFound, seg, v.Type())
+			}
+			v = v.MapIndex(reflect.ValueOf(seg).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				if n == len(segments)-1 {
+					return v, nil
+				}
+				return reflect.Value{}, fmt.Errorf("%w: missing key evaluating %q", ErrNilInPath, strings.Join(segments[:n+1], "."))
+			}
+		case reflect.Invalid:
+			return reflect.Value{}, fmt.Errorf("%w: evaluating %q", ErrNilInPath, strings.Join(segments[:n+1], "."))
+		default:
+			return reflect.Value{}, fmt.Errorf("%w: %q in %s", ErrFieldNotFound, seg, v.Type())
+		}
+	}
+	return v, nil
+}
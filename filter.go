@@ -6,7 +6,31 @@ import (
 )
 
 func FilterTemplateFunc(slice any, f any) (any, error) {
-	av := reflect.ValueOf(slice)
+	return defaultOperations.filterTemplateFunc(slice, f)
+}
+
+func (o *operations) filterTemplateFunc(slice any, f any) (any, error) {
+	ee := o.elementErrors()
+	r, err := o.filterSlice(slice, f, ee)
+	if err != nil {
+		return nil, opError("filter", f, err)
+	}
+	if err := ee.err("filter", f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// filterSlice keeps the elements of slice f returns true for. Elements which fail are handled by ee, a substitute
+// standing in for the result of f.
+func (o *operations) filterSlice(slice any, f any, ee *elementErrors) (any, error) {
+	if r, ok, err := o.fastFilter(slice, f, ee); ok {
+		return r, err
+	}
+	av, err := o.seqToSlice(reflect.ValueOf(slice))
+	if err != nil {
+		return nil, err
+	}
 	if av.Kind() != reflect.Slice && av.Kind() != reflect.Invalid {
 		return nil, fmt.Errorf("%w not %s", ErrExpectedFirstParameterToBeSlice, av.Kind())
 	}
@@ -15,7 +39,12 @@ func FilterTemplateFunc(slice any, f any) (any, error) {
 		return nil, ErrExpected2ndArgumentToBeFunction
 	}
 
-	numIn := fv.Type().NumIn()
+	var sliceElemType reflect.Type
+	if av.Kind() == reflect.Slice {
+		sliceElemType = av.Type().Elem()
+	}
+	p := planFor(fv.Type(), sliceElemType)
+	numIn := len(p.in)
 	if numIn > 1 {
 		return nil, ErrInputFuncMustTake0or1Arguments
 	}
@@ -24,47 +53,38 @@ func FilterTemplateFunc(slice any, f any) (any, error) {
 	checkInside := false
 
 	if numIn == 1 {
-		elemType = fv.Type().In(0)
-		if av.Kind() == reflect.Slice {
-			sliceElemType := av.Type().Elem()
+		elemType = p.in[0]
+		if sliceElemType != nil {
 			// If the slice contains interfaces, we cannot statically guarantee that
 			// the dynamic values inside are assignable to the function argument type.
 			// We must check inside the loop to provide a friendly error instead of panicking,
 			// or to allow valid assignments if the types align (e.g. interface{} -> interface{}).
-			if sliceElemType.Kind() == reflect.Interface {
+			if p.elemInterface || (!p.elemAssignable && o.coercion == CoercionNumeric) {
 				checkInside = true
-			} else if !sliceElemType.AssignableTo(elemType) {
-				return nil, fmt.Errorf("item 0 not assignable to: %s", elemType)
+			} else if !p.elemAssignable {
+				return nil, &OpError{Index: -1, Cause: fmt.Errorf("elements %w: %s", ErrItemNotAssignable, elemType)}
 			}
 		}
 	} else {
 		// NumIn == 0
-		if av.Kind() == reflect.Slice {
-			elemType = av.Type().Elem()
+		if sliceElemType != nil {
+			elemType = sliceElemType
 		} else {
 			// av is Invalid (nil)
-			elemType = reflect.TypeOf((*interface{})(nil)).Elem()
+			elemType = anyType
 		}
 	}
 
-	switch fv.Type().NumOut() {
-	case 1:
-		fvfrt := fv.Type().Out(0)
-		if !fvfrt.AssignableTo(reflect.TypeOf(true)) {
-			return nil, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, fvfrt)
-		}
-	case 2:
-		fvsrt := fv.Type().Out(1)
-		errorType := reflect.TypeOf((*error)(nil)).Elem()
-		if !fvsrt.AssignableTo(errorType) {
-			return nil, fmt.Errorf("%w instead got: %s", ErrExpectedSecondReturnToBeError, fvsrt)
+	switch p.numOut {
+	case 1, 2:
+		if p.outErr != nil {
+			return nil, p.outErr
 		}
-		fvfrt := fv.Type().Out(0)
-		if !fvfrt.AssignableTo(reflect.TypeOf(true)) {
-			return nil, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, fvfrt)
+		if !p.returnsBool {
+			return nil, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, p.out)
 		}
 	default:
-		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, fv.Type().NumOut())
+		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
 	}
 
 	l := 0
@@ -82,7 +102,13 @@ func FilterTemplateFunc(slice any, f any) (any, error) {
 	}
 
 	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
 		var r []reflect.Value
+		var elementErr error
+		// keep is the value appended to the result if the element passes
+		keep := av.Index(i)
 		if numIn == 1 {
 			ev := av.Index(i)
 			arg := ev
@@ -93,32 +119,42 @@ func FilterTemplateFunc(slice any, f any) (any, error) {
 						case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Func, reflect.Interface:
 							arg = reflect.Zero(elemType)
 						default:
-							return nil, fmt.Errorf("item %d is nil, not assignable to: %s", i, elemType)
+							elementErr = elementError(i, ev, fmt.Errorf("is nil, %w: %s", ErrItemNotAssignable, elemType))
 						}
 					} else {
 						arg = ev.Elem()
 					}
 				}
-				if !arg.Type().AssignableTo(elemType) {
-					return nil, fmt.Errorf("item %d not assignable to: %s", i, elemType)
+				if elementErr == nil {
+					arg, elementErr = o.assign(i, arg, elemType)
 				}
 			}
-			args[0] = arg
-			r = fv.Call(args)
+			if elementErr == nil {
+				args[0] = arg
+				keep = arg
+				r, elementErr = o.call(fv, p, i, ev, args)
+			}
 		} else {
-			r = fv.Call(nil)
+			r, elementErr = o.call(fv, p, i, av.Index(i), nil)
 		}
 
-		if len(r) == 2 && !r[1].IsNil() {
-			return nil, fmt.Errorf("f execution number %d returned: %w", i, r[1].Interface().(error))
+		if elementErr == nil && len(r) == 2 && !r[1].IsNil() {
+			elementErr = callbackError(i, av.Index(i), r[1].Interface().(error))
+		}
+		if elementErr != nil {
+			sv, err := ee.handle(i, elementErr, boolType)
+			if err != nil {
+				return nil, err
+			}
+			// Elements which could not be passed to f cannot be kept in the result either
+			if !sv.IsValid() || !keep.Type().AssignableTo(elemType) {
+				continue
+			}
+			r = []reflect.Value{sv}
 		}
 
 		if r[0].Bool() {
-			if numIn == 1 {
-				nra = reflect.Append(nra, args[0])
-			} else {
-				nra = reflect.Append(nra, av.Index(i))
-			}
+			nra = reflect.Append(nra, keep)
 		}
 	}
 
@@ -6,9 +6,17 @@ import (
 )
 
 func FindTemplateFunc(slice any, f any) (any, error) {
-	i, err := FindIndexTemplateFunc(slice, f)
+	return defaultOperations.find(slice, f)
+}
+
+func (o *operations) find(slice any, f any) (any, error) {
+	slice, err := o.collectSeq(slice)
 	if err != nil {
-		return nil, err
+		return nil, opError("find", f, err)
+	}
+	i, err := o.findIndex(slice, f)
+	if err != nil {
+		return nil, opError("find", f, err)
 	}
 	if i == -1 {
 		return nil, nil
@@ -18,7 +26,25 @@ func FindTemplateFunc(slice any, f any) (any, error) {
 }
 
 func FindIndexTemplateFunc(slice any, f any) (int, error) {
-	av := reflect.ValueOf(slice)
+	return defaultOperations.findIndexTemplateFunc(slice, f)
+}
+
+func (o *operations) findIndexTemplateFunc(slice any, f any) (int, error) {
+	i, err := o.findIndex(slice, f)
+	if err != nil {
+		return -1, opError("findIndex", f, err)
+	}
+	return i, nil
+}
+
+func (o *operations) findIndex(slice any, f any) (int, error) {
+	if i, ok, err := o.fastFindIndex(slice, f); ok {
+		return i, err
+	}
+	av, err := o.seqToSlice(reflect.ValueOf(slice))
+	if err != nil {
+		return -1, err
+	}
 	if av.Kind() != reflect.Slice && av.Kind() != reflect.Invalid {
 		return -1, fmt.Errorf("%w not %s", ErrExpectedFirstParameterToBeSlice, av.Kind())
 	}
@@ -26,52 +52,63 @@ func FindIndexTemplateFunc(slice any, f any) (int, error) {
 	if fv.Kind() != reflect.Func {
 		return -1, ErrExpected2ndArgumentToBeFunction
 	}
+	l := 0
+	var elemType reflect.Type
+	if av.Kind() != reflect.Invalid && !av.IsNil() {
+		l = av.Len()
+		elemType = av.Type().Elem()
+	}
+	p := planFor(fv.Type(), elemType)
 	var fvfpt reflect.Type
-	switch fv.Type().NumIn() {
+	switch len(p.in) {
 	case 0:
 	case 1:
-		fvfpt = fv.Type().In(0)
+		fvfpt = p.in[0]
 	default:
 		return -1, ErrInputFuncMustTake0or1Arguments
 	}
-	switch fv.Type().NumOut() {
-	case 1:
-		fvfrt := fv.Type().Out(0)
-		if !fvfrt.AssignableTo(reflect.TypeOf(true)) {
-			return -1, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, fvfrt)
+	switch p.numOut {
+	case 1, 2:
+		if p.outErr != nil {
+			return -1, p.outErr
 		}
-	case 2:
-		fvsrt := fv.Type().Out(1)
-		if !fvsrt.AssignableTo(reflect.TypeOf(error(nil))) {
-			return -1, fmt.Errorf("%w instead got: %s", ErrExpectedSecondReturnToBeError, fvsrt)
+		if !p.returnsBool {
+			return -1, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, p.out)
 		}
 	default:
-		return -1, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, fv.Type().NumOut())
+		return -1, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
 	}
-	l := 0
-	if av.Kind() != reflect.Invalid && !av.IsNil() {
-		l = av.Len()
-	}
-	switch fv.Type().NumIn() {
+	// Elements only need checking one by one when their type is not known to be assignable
+	checkEach := !p.elemAssignable || p.elemInterface
+	switch len(p.in) {
 	case 1:
 		if fvfpt != nil {
 			for i := 0; i < l; i++ {
+				if err := o.checkContext(i); err != nil {
+					return -1, err
+				}
 				ev := av.Index(i)
-				if ev.Kind() == reflect.Interface && !ev.IsNil() {
-					ev = ev.Elem()
+				if checkEach {
+					if ev.Kind() == reflect.Interface && !ev.IsNil() {
+						ev = ev.Elem()
+					}
+					var err error
+					if ev, err = o.assign(i, ev, fvfpt); err != nil {
+						return -1, err
+					}
 				}
-				if !ev.Type().AssignableTo(fvfpt) {
-					return -1, fmt.Errorf("item %d not assignable to: %s", i, fvfpt)
+				r, err := o.call(fv, p, i, ev, []reflect.Value{ev})
+				if err != nil {
+					return -1, err
 				}
-				r := fv.Call([]reflect.Value{ev})
 				if r == nil {
 					continue
 				}
 				if len(r) != 1 && len(r) != 2 {
-					return -1, fmt.Errorf("f execution number %d returned: %d results %w", i, len(r), ErrExpected1Or2ReturnTypes)
+					return -1, elementError(i, ev, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, len(r)))
 				}
 				if len(r) == 2 && !r[1].IsNil() {
-					return -1, fmt.Errorf("f execution number %d returned: %w", i, r[1].Interface().(error))
+					return -1, callbackError(i, ev, r[1].Interface().(error))
 				}
 				if b1, b2 := r[0].Interface().(bool); b1 && b2 {
 					return i, nil
@@ -82,15 +119,21 @@ func FindIndexTemplateFunc(slice any, f any) (int, error) {
 		fallthrough
 	case 0:
 		for i := 0; i < l; i++ {
-			r := fv.Call([]reflect.Value{})
+			if err := o.checkContext(i); err != nil {
+				return -1, err
+			}
+			r, err := o.call(fv, p, i, av.Index(i), []reflect.Value{})
+			if err != nil {
+				return -1, err
+			}
 			if r == nil {
 				continue
 			}
 			if len(r) != 1 && len(r) != 2 {
-				return -1, fmt.Errorf("f execution number %d returned: %d results %w", i, len(r), ErrExpected1Or2ReturnTypes)
+				return -1, elementError(i, av.Index(i), fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, len(r)))
 			}
 			if len(r) == 2 && !r[1].IsNil() {
-				return -1, fmt.Errorf("f execution number %d returned: %w", i, r[1].Interface().(error))
+				return -1, callbackError(i, av.Index(i), r[1].Interface().(error))
 			}
 			if b1, b2 := r[0].Interface().(bool); b1 && b2 {
 				return i, nil
@@ -101,3 +144,33 @@ func FindIndexTemplateFunc(slice any, f any) (int, error) {
 		return -1, ErrInputFuncMustTake0or1Arguments
 	}
 }
+
+type FindResult struct {
+	Found bool
+	Value any
+	Index int
+}
+
+func FindResultTemplateFunc(slice any, f any) (FindResult, error) {
+	return defaultOperations.findResult(slice, f)
+}
+
+func (o *operations) findResult(slice any, f any) (FindResult, error) {
+	slice, err := o.collectSeq(slice)
+	if err != nil {
+		return FindResult{Index: -1}, opError("findResult", f, err)
+	}
+	i, err := o.findIndex(slice, f)
+	if err != nil {
+		return FindResult{Index: -1}, opError("findResult", f, err)
+	}
+	if i == -1 {
+		return FindResult{Index: -1}, nil
+	}
+	av := reflect.ValueOf(slice)
+	return FindResult{
+		Found: true,
+		Value: av.Index(i).Interface(),
+		Index: i,
+	}, nil
+}
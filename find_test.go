@@ -232,3 +232,76 @@ func TestFindIndexTemplateFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestFindResultTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "first Odd",
+			template:   "{{ with findResult $.DataInts $.Funcs.odd }}{{ .Found }} {{ .Value }} {{ .Index }}{{ end }}",
+			want:       "true 1 0",
+			correctErr: NoError,
+		},
+		{
+			name:       "False is not found",
+			template:   "{{ with findResult $.DataInts $.Funcs.false }}{{ .Found }} {{ .Value }} {{ .Index }}{{ end }}",
+			want:       "false <no value> -1",
+			correctErr: NoError,
+		},
+		{
+			name:       "Nil element is found",
+			template:   "{{ with findResult $.DataPtrs $.Funcs.isNil }}{{ .Found }} {{ .Value }} {{ .Index }}{{ end }}",
+			want:       "true <nil> 1",
+			correctErr: NoError,
+		},
+		{
+			name:       "Nil slice is not found",
+			template:   "{{ with findResult nil $.Funcs.false }}{{ .Found }} {{ .Index }}{{ end }}",
+			want:       "false -1",
+			correctErr: NoError,
+		},
+		{
+			name:       "Int returns error",
+			template:   "{{ findResult $.DataInts $.Funcs.inc }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstReturnToBeBool),
+		},
+	}
+	funcs := misc.MergeMaps(TextFunctions(), misc.SimpleTextFunctions())
+	funcs["isNil"] = func(p *int) bool { return p == nil }
+	one := 1
+	data := struct {
+		DataInts []int
+		DataPtrs []*int
+		Funcs    map[string]any
+	}{
+		DataInts: []int{1, 2, 3, 4},
+		DataPtrs: []*int{&one, nil},
+		Funcs:    funcs,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("FindResultTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("FindResultTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package funtemplates
+
+import (
+	"reflect"
+)
+
+// Map returns the result of f for every element of s.
+func Map[T, U any](s []T, f func(T) U) []U {
+	r, _ := mapEach(defaultOperations, s, noErr(f), nil)
+	return r
+}
+
+// MapErr is Map for a function which can fail. It stops at the first error, returning an *OpError.
+func MapErr[T, U any](s []T, f func(T) (U, error)) ([]U, error) {
+	r, err := mapEach(defaultOperations, s, withIndex(f), nil)
+	if err != nil {
+		return nil, opError("map", f, err)
+	}
+	return r, nil
+}
+
+// Filter returns the elements of s f returns true for.
+func Filter[T any](s []T, f func(T) bool) []T {
+	r, _ := filterEach(defaultOperations, s, noErr(f), nil)
+	return r
+}
+
+// FilterErr is Filter for a function which can fail. It stops at the first error, returning an *OpError.
+func FilterErr[T any](s []T, f func(T) (bool, error)) ([]T, error) {
+	r, err := filterEach(defaultOperations, s, withIndex(f), nil)
+	if err != nil {
+		return nil, opError("filter", f, err)
+	}
+	return r, nil
+}
+
+// Find returns the first element of s f returns true for, and whether there was one.
+func Find[T any](s []T, f func(T) bool) (T, bool) {
+	i, _ := findIndexEach(defaultOperations, s, noErr(f))
+	if i == -1 {
+		var zero T
+		return zero, false
+	}
+	return s[i], true
+}
+
+// FindErr is Find for a function which can fail.
+func FindErr[T any](s []T, f func(T) (bool, error)) (T, bool, error) {
+	var zero T
+	i, err := findIndexEach(defaultOperations, s, withIndex(f))
+	if err != nil {
+		return zero, false, opError("find", f, err)
+	}
+	if i == -1 {
+		return zero, false, nil
+	}
+	return s[i], true, nil
+}
+
+// FindIndex returns the index of the first element of s f returns true for, or -1.
+func FindIndex[T any](s []T, f func(T) bool) int {
+	i, _ := findIndexEach(defaultOperations, s, noErr(f))
+	return i
+}
+
+// FindIndexErr is FindIndex for a function which can fail.
+func FindIndexErr[T any](s []T, f func(T) (bool, error)) (int, error) {
+	i, err := findIndexEach(defaultOperations, s, withIndex(f))
+	if err != nil {
+		return -1, opError("findIndex", f, err)
+	}
+	return i, nil
+}
+
+// mapEach is the core of Map and of the map template function's fast paths. f is called with the index of each
+// element and returns element errors, which are handled by ee, or returned straight away when ee is nil. Like the
+// other core functions it stops once o's context is done.
+func mapEach[T, U any](o *operations, s []T, f func(int, T) (U, error), ee *elementErrors) ([]U, error) {
+	if err := o.checkInput(len(s)); err != nil {
+		return nil, err
+	}
+	r := make([]U, 0, len(s))
+	for i, v := range s {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		u, err := f(i, v)
+		if err != nil {
+			sv, err := handleElement(ee, i, err, reflect.TypeFor[U]())
+			if err != nil {
+				return nil, err
+			}
+			if !sv.IsValid() {
+				continue
+			}
+			u, _ = sv.Interface().(U)
+		}
+		r = append(r, u)
+	}
+	return r, nil
+}
+
+// filterEach is the core of Filter, a substitute from ee standing in for the result of f.
+func filterEach[T any](o *operations, s []T, f func(int, T) (bool, error), ee *elementErrors) ([]T, error) {
+	if err := o.checkInput(len(s)); err != nil {
+		return nil, err
+	}
+	r := make([]T, 0, len(s))
+	for i, v := range s {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		keep, err := f(i, v)
+		if err != nil {
+			sv, err := handleElement(ee, i, err, boolType)
+			if err != nil {
+				return nil, err
+			}
+			keep = sv.IsValid() && sv.Bool()
+		}
+		if keep {
+			r = append(r, v)
+		}
+	}
+	return r, nil
+}
+
+func findIndexEach[T any](o *operations, s []T, f func(int, T) (bool, error)) (int, error) {
+	if err := o.checkInput(len(s)); err != nil {
+		return -1, err
+	}
+	for i, v := range s {
+		if err := o.checkContext(i); err != nil {
+			return -1, err
+		}
+		found, err := f(i, v)
+		if err != nil {
+			return -1, err
+		}
+		if found {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+func handleElement(ee *elementErrors, i int, err error, t reflect.Type) (reflect.Value, error) {
+	if ee == nil {
+		return reflect.Value{}, err
+	}
+	return ee.handle(i, err, t)
+}
+
+// noErr adapts a function which cannot fail to the signature of the core functions.
+func noErr[T, U any](f func(T) U) func(int, T) (U, error) {
+	return func(_ int, v T) (U, error) {
+		return f(v), nil
+	}
+}
+
+// withIndex adapts f to the signature of the core functions, wrapping its errors as the errors of element i.
+func withIndex[T, U any](f func(T) (U, error)) func(int, T) (U, error) {
+	return func(i int, v T) (U, error) {
+		u, err := f(v)
+		if err != nil {
+			return u, callbackError(i, valueOf(v), err)
+		}
+		return u, nil
+	}
+}
+
+// valueOf returns v as a reflect.Value of type T, so interface values keep their interface type.
+func valueOf[T any](v T) reflect.Value {
+	return reflect.ValueOf(&v).Elem()
+}
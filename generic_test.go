@@ -0,0 +1,149 @@
+package funtemplates
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGeneric(t *testing.T) {
+	data := []int{1, 2, 3, 4}
+	even := func(i int) bool { return i%2 == 0 }
+	failOnThree := func(i int) (bool, error) {
+		if i == 3 {
+			return false, errTest
+		}
+		return i > 1, nil
+	}
+
+	if diff := cmp.Diff([]string{"1", "2", "3", "4"}, Map(data, strconv.Itoa)); diff != "" {
+		t.Errorf("Map diff =\n %s", diff)
+	}
+	if diff := cmp.Diff([]int{2, 4}, Filter(data, even)); diff != "" {
+		t.Errorf("Filter diff =\n %s", diff)
+	}
+	if v, ok := Find(data, even); v != 2 || !ok {
+		t.Errorf("Find got %d %t", v, ok)
+	}
+	if _, ok := Find(data, func(i int) bool { return i > 4 }); ok {
+		t.Error("Find expected nothing to be found")
+	}
+	if i := FindIndex(data, even); i != 1 {
+		t.Errorf("FindIndex got %d", i)
+	}
+
+	r, err := MapErr(data, func(i int) (int, error) { return i * 2, nil })
+	if diff := cmp.Diff([]int{2, 4, 6, 8}, r); diff != "" || err != nil {
+		t.Errorf("MapErr %v diff =\n %s", err, diff)
+	}
+	_, err = MapErr(data, func(i int) (int, error) { return 0, errTest })
+	var oe *OpError
+	if !errors.As(err, &oe) || oe.Op != "map" || oe.Index != 0 || !errors.Is(err, errTest) {
+		t.Errorf("MapErr got %v", err)
+	}
+	if _, err := FilterErr(data, failOnThree); !errors.Is(err, ErrCallbackReturnedError) {
+		t.Errorf("FilterErr got %v", err)
+	}
+	if v, ok, err := FindErr(data, failOnThree); v != 2 || !ok || err != nil {
+		t.Errorf("FindErr got %d %t %v", v, ok, err)
+	}
+	if i, err := FindIndexErr(data, func(i int) (bool, error) { return failOnThree(i + 2) }); i != -1 || !errors.Is(err, errTest) {
+		t.Errorf("FindIndexErr got %d %v", i, err)
+	}
+}
+
+type ints []int
+
+type strs []string
+
+// TestFastPaths checks the fast paths against the reflection based implementations, which named slice types use.
+func TestFastPaths(t *testing.T) {
+	failOnThree := func(i int) (string, error) {
+		if i == 3 {
+			return "", errTest
+		}
+		return strconv.Itoa(i), nil
+	}
+	tests := []struct {
+		name string
+		fast func() (any, error)
+		slow func() (any, error)
+	}{
+		{
+			name: "map int to string",
+			fast: func() (any, error) { return MapTemplateFunc([]int{1, 2}, strconv.Itoa) },
+			slow: func() (any, error) { return MapTemplateFunc(ints{1, 2}, strconv.Itoa) },
+		},
+		{
+			name: "map string to any",
+			fast: func() (any, error) { return MapTemplateFunc([]string{"a"}, func(s string) any { return s }) },
+			slow: func() (any, error) { return MapTemplateFunc(strs{"a"}, func(s string) any { return s }) },
+		},
+		{
+			name: "map error",
+			fast: func() (any, error) { return MapTemplateFunc([]int{1, 3}, failOnThree) },
+			slow: func() (any, error) { return MapTemplateFunc(ints{1, 3}, failOnThree) },
+		},
+		{
+			name: "map nothing with an error return",
+			fast: func() (any, error) { return MapTemplateFunc([]int{}, failOnThree) },
+			slow: func() (any, error) { return MapTemplateFunc(ints{}, failOnThree) },
+		},
+		{
+			name: "map skipping errors",
+			fast: func() (any, error) {
+				return TextFunctions(WithErrorPolicy(ErrorPolicySkip))["map"].(func(any, any) (any, error))([]int{1, 3}, failOnThree)
+			},
+			slow: func() (any, error) {
+				return TextFunctions(WithErrorPolicy(ErrorPolicySkip))["map"].(func(any, any) (any, error))(ints{1, 3}, failOnThree)
+			},
+		},
+		{
+			name: "filter",
+			fast: func() (any, error) {
+				return FilterTemplateFunc([]string{"a", "", "b"}, func(s string) bool { return s != "" })
+			},
+			slow: func() (any, error) {
+				return FilterTemplateFunc(strs{"a", "", "b"}, func(s string) bool { return s != "" })
+			},
+		},
+		{
+			name: "findIndex panic",
+			fast: func() (any, error) { return FindIndexTemplateFunc([]int{1, 0}, func(i int) bool { return 1/i == 0 }) },
+			slow: func() (any, error) { return FindIndexTemplateFunc(ints{1, 0}, func(i int) bool { return 1/i == 0 }) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fast, fastErr := tt.fast()
+			slow, slowErr := tt.slow()
+			if diff := cmp.Diff(fmt.Sprintf("%T %v", slow, slow), fmt.Sprintf("%T %v", fast, fast)); diff != "" {
+				t.Errorf("result diff =\n %s", diff)
+			}
+			if fmt.Sprint(slowErr) != fmt.Sprint(fastErr) {
+				t.Errorf("got error %v, want %v", fastErr, slowErr)
+			}
+		})
+	}
+}
+
+func BenchmarkMapTemplateFunc_Strings(b *testing.B) {
+	data := strings.Fields("the quick brown fox jumps over the lazy dog")
+	for i := 0; i < b.N; i++ {
+		if _, err := MapTemplateFunc(data, strings.ToUpper); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapTemplateFunc_StringsReflect(b *testing.B) {
+	data := strs(strings.Fields("the quick brown fox jumps over the lazy dog"))
+	for i := 0; i < b.N; i++ {
+		if _, err := MapTemplateFunc(data, strings.ToUpper); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
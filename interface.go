@@ -1,24 +1,202 @@
 package funtemplates
 
 import (
+	"fmt"
 	ht "html/template"
+	"maps"
+	"slices"
+	"sync/atomic"
 	tt "text/template"
+	"unicode"
 )
 
-func TextFunctions() tt.FuncMap {
-	return map[string]any{
-		"filter":    FilterTemplateFunc,
-		"find":      FindTemplateFunc,
-		"findIndex": FindIndexTemplateFunc,
-		"map":       MapTemplateFunc,
+// Functions is one configuration of the template functions, from which the FuncMaps for both text/template and
+// html/template are made. They share the configuration, including the budget and context.
+type Functions struct {
+	o *operations
+	// names holds the name in templates of each operation registered
+	names map[string]string
+}
+
+// New returns the functions configured by opts. Besides the behaviour of the operations the options choose which are
+// registered and under what names, and New fails when they name an operation which does not exist, would register
+// two operations under the same name, or set an unknown error policy or coercion.
+func New(opts ...Option) (*Functions, error) {
+	o := newOperations(opts...)
+	if _, err := newElementErrors(o.errorPolicy, o.substitute); err != nil {
+		return nil, err
+	}
+	if err := o.coercion.check(); err != nil {
+		return nil, err
+	}
+	names, err := o.templateNames()
+	if err != nil {
+		return nil, err
+	}
+	return &Functions{o: o, names: names}, nil
+}
+
+// Text returns the FuncMap for text/template.
+func (f *Functions) Text() tt.FuncMap {
+	return f.funcMap(false)
+}
+
+// Html returns the FuncMap for html/template.
+func (f *Functions) Html() ht.FuncMap {
+	return f.funcMap(true)
+}
+
+func (f *Functions) funcMap(html bool) map[string]any {
+	m := make(map[string]any, len(f.names))
+	for op, fn := range f.o.funcs(html) {
+		name, ok := f.names[op]
+		if !ok {
+			continue
+		}
+		if f.o.observer != nil {
+			fn = f.o.observed(op, fn)
+		}
+		m[name] = fn
+	}
+	return m
+}
+
+// ForExecution returns the functions with the same configuration and a budget of their own, to limit one template
+// execution. With Budget.MaxCalls templates must be executed with these. Add their FuncMap to a clone of the parsed template, as the functions of a template can be replaced
+// after parsing:
+//
+//	t, _ := tmpl.Clone()
+//	t.Funcs(f.ForExecution().Html())
+//	err := t.Execute(w, data)
+func (f *Functions) ForExecution() *Functions {
+	o := *f.o
+	o.calls = new(atomic.Int64)
+	return &Functions{o: &o, names: f.names}
+}
+
+// TextFunctions returns the functions for text/template. It panics when the options are invalid, see New.
+func TextFunctions(opts ...Option) tt.FuncMap {
+	return mustNew(opts).Text()
+}
+
+// HtmlFunctions returns the functions for html/template. It panics when the options are invalid, see New.
+func HtmlFunctions(opts ...Option) ht.FuncMap {
+	return mustNew(opts).Html()
+}
+
+// mustNew is New for TextFunctions and HtmlFunctions, which also refuse Budget.MaxCalls as their callers cannot
+// use ForExecution.
+func mustNew(opts []Option) *Functions {
+	f, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if f.o.budget.MaxCalls > 0 {
+		panic(fmt.Errorf("%w: MaxCalls is counted per execution, use New and ForExecution", ErrNotForExecution))
+	}
+	return f
+}
+
+// funcs returns every operation by name, with the html/template variants when html is true.
+func (o *operations) funcs(html bool) map[string]any {
+	m := map[string]any{
+		"avg":                  o.avg,
+		"cartesianProduct":     o.cartesianProduct,
+		"cartesianProductIter": o.cartesianProductIter,
+		"collect":              o.collect,
+		"combinations":         o.combinations,
+		"combinationsIter":     o.combinationsIter,
+		"countBy":              o.countBy,
+		"difference":           o.difference,
+		"filter":               o.filterTemplateFunc,
+		"filterTry":            o.filterTry,
+		"find":                 o.find,
+		"findIndex":            o.findIndexTemplateFunc,
+		"findResult":           o.findResult,
+		"first":                o.first,
+		"indexBy":              o.indexBy,
+		"intersect":            o.intersect,
+		"join":                 o.join,
+		"joinWith":             o.joinWith,
+		"keyBy":                o.keyBy,
+		"last":                 o.last,
+		"lazyFilter":           o.lazyFilter,
+		"lazyMap":              o.lazyMap,
+		"map":                  o.mapTemplateFunc,
+		"mapTry":               o.mapTry,
+		"max":                  o.max,
+		"maxBy":                o.maxBy,
+		"min":                  o.min,
+		"minBy":                o.minBy,
+		"nth":                  o.nth,
+		"permutations":         o.permutations,
+		"permutationsIter":     o.permutationsIter,
+		"pfilter":              o.pfilter,
+		"pluck":                o.pluck,
+		"pmap":                 o.pmap,
+		"product":              o.product,
+		"scan":                 o.scan,
+		"select":               o.selectFields,
+		"seq":                  o.seq,
+		"seqIter":              o.seqIter,
+		"sum":                  o.sum,
+		"symmetricDifference":  o.symmetricDifference,
+		"take":                 o.take,
+		"tally":                o.tally,
+		"transpose":            o.transpose,
+		"union":                o.union,
+	}
+	if html {
+		m["join"] = o.htmlJoin
+		m["joinWith"] = o.htmlJoinWith
+	}
+	return m
+}
+
+// templateNames returns the name in templates of each operation to register.
+func (o *operations) templateNames() (map[string]string, error) {
+	ops := o.funcs(false)
+	for _, set := range []map[string]bool{o.include, o.exclude} {
+		for op := range set {
+			if _, ok := ops[op]; !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownOperation, op)
+			}
+		}
+	}
+	for op := range o.renames {
+		if _, ok := ops[op]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownOperation, op)
+		}
+	}
+	names := map[string]string{}
+	registered := map[string]string{}
+	for _, op := range slices.Sorted(maps.Keys(ops)) {
+		if (o.include != nil && !o.include[op]) || o.exclude[op] {
+			continue
+		}
+		name := op
+		if r, ok := o.renames[op]; ok {
+			name = r
+		}
+		name = o.prefix + name
+		if !isIdentifier(name) {
+			return nil, fmt.Errorf("%w: %q for %s", ErrInvalidFunctionName, name, op)
+		}
+		if other, ok := registered[name]; ok {
+			return nil, fmt.Errorf("%w: %q for both %s and %s", ErrDuplicateFunctionName, name, other, op)
+		}
+		names[op] = name
+		registered[name] = op
 	}
+	return names, nil
 }
 
-func HtmlFunctions() ht.FuncMap {
-	return map[string]any{
-		"filter":    FilterTemplateFunc,
-		"find":      FindTemplateFunc,
-		"findIndex": FindIndexTemplateFunc,
-		"map":       MapTemplateFunc,
+// isIdentifier reports whether name can be used as a function name in templates.
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
 	}
+	return name != ""
 }
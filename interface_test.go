@@ -0,0 +1,197 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	ht "html/template"
+	"maps"
+	"slices"
+	"testing"
+	"text/template"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Defaults",
+			template:   "{{ map $.Data $.Inc }} {{ sum $.Data }}",
+			want:       "[2 3 4] 6",
+			correctErr: NoError,
+		},
+		{
+			name:       "Prefix",
+			opts:       []Option{WithPrefix("fp_")},
+			template:   "{{ fp_map $.Data $.Inc }} {{ fp_sum $.Data }}",
+			want:       "[2 3 4] 6",
+			correctErr: NoError,
+		},
+		{
+			name:       "Rename",
+			opts:       []Option{WithRename("map", "fmap")},
+			template:   "{{ fmap $.Data $.Inc }}",
+			want:       "[2 3 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Renamed operations are prefixed",
+			opts:       []Option{WithPrefix("fp"), WithRename("map", "Map")},
+			template:   "{{ fpMap $.Data $.Inc }} {{ fpsum $.Data }}",
+			want:       "[2 3 4] 6",
+			correctErr: NoError,
+		},
+		{
+			name:       "Behaviours",
+			opts:       []Option{WithPrefix("fp_"), WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ fp_map $.Data $.Fail }}",
+			want:       "[1 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Unknown operation",
+			opts:       []Option{WithInclude("map", "fold")},
+			correctErr: ErrorIs(ErrUnknownOperation),
+		},
+		{
+			name:       "Unknown operation renamed",
+			opts:       []Option{WithRename("fold", "reduce")},
+			correctErr: ErrorIs(ErrUnknownOperation),
+		},
+		{
+			name:       "Duplicate names",
+			opts:       []Option{WithRename("map", "filter")},
+			correctErr: ErrorIs(ErrDuplicateFunctionName),
+		},
+		{
+			name:       "Renamed out of the way",
+			opts:       []Option{WithRename("map", "filter"), WithExclude("filter")},
+			template:   "{{ filter $.Data $.Inc }}",
+			want:       "[2 3 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Unknown error policy",
+			opts:       []Option{WithErrorPolicy("bogus")},
+			correctErr: ErrorIs(ErrUnknownPolicy),
+		},
+		{
+			name:       "Unknown coercion",
+			opts:       []Option{WithCoercion("bogus")},
+			correctErr: ErrorIs(ErrUnknownCoercion),
+		},
+		{
+			name:       "Invalid name",
+			opts:       []Option{WithPrefix("fp.")},
+			correctErr: ErrorIs(ErrInvalidFunctionName),
+		},
+	}
+	data := map[string]any{
+		"Data": []int{1, 2, 3},
+		"Inc":  func(i int) int { return i + 1 },
+		"Fail": func(i int) (int, error) {
+			if i == 2 {
+				return 0, errTest
+			}
+			return i, nil
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.opts...)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("New got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			for _, html := range []bool{false, true} {
+				got := bytes.NewBuffer(nil)
+				if html {
+					tmpl := ht.Must(ht.New("").Funcs(f.Html()).Parse(tt.template))
+					err = tmpl.Execute(got, data)
+				} else {
+					tmpl := template.Must(template.New("").Funcs(f.Text()).Parse(tt.template))
+					err = tmpl.Execute(got, data)
+				}
+				if err != nil {
+					t.Fatalf("html %v: %v", html, err)
+				}
+				if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+					t.Errorf("html %v diff =\n %s", html, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "Include",
+			opts: []Option{WithInclude("map", "filter"), WithInclude("sum")},
+			want: []string{"filter", "map", "sum"},
+		},
+		{
+			name: "Include and exclude",
+			opts: []Option{WithInclude("map", "filter"), WithExclude("filter")},
+			want: []string{"map"},
+		},
+		{
+			name: "Include with a prefix",
+			opts: []Option{WithInclude("keyBy", "indexBy"), WithPrefix("fp_")},
+			want: []string{"fp_indexBy", "fp_keyBy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, slices.Sorted(maps.Keys(f.Text()))); diff != "" {
+				t.Errorf("text names diff =\n %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, slices.Sorted(maps.Keys(f.Html()))); diff != "" {
+				t.Errorf("html names diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestTextAndHtmlShareTheConfiguration(t *testing.T) {
+	f, err := New(WithBudget(Budget{MaxCalls: 4}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f = f.ForExecution()
+	inc := func(i int) int { return i + 1 }
+	if _, err := f.Text()["map"].(func(any, any) (any, error))([]int{1, 2, 3}, inc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Html()["map"].(func(any, any) (any, error))([]int{1, 2, 3}, inc); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the calls of both FuncMaps to count against the budget, got %v", err)
+	}
+}
+
+func TestTextFunctionsPanicsOnInvalidOptions(t *testing.T) {
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrUnknownOperation) {
+			t.Errorf("expected a panic with ErrUnknownOperation, got %v", err)
+		}
+	}()
+	TextFunctions(WithExclude("fold"))
+}
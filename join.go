@@ -0,0 +1,151 @@
+package funtemplates
+
+import (
+	"fmt"
+	ht "html/template"
+	"reflect"
+	"strings"
+)
+
+// JoinTemplateFunc formats each element with fmt.Sprint and joins them with sep. The optional lastSep is used
+// between the final two elements instead, for lists like "a, b and c".
+func JoinTemplateFunc(slice any, sep string, lastSep ...string) (string, error) {
+	return defaultOperations.join(slice, sep, lastSep...)
+}
+
+// JoinWithTemplateFunc is JoinTemplateFunc formatting each element with f instead.
+func JoinWithTemplateFunc(slice any, f any, sep string, lastSep ...string) (string, error) {
+	return defaultOperations.joinWith(slice, f, sep, lastSep...)
+}
+
+func (o *operations) join(slice any, sep string, lastSep ...string) (string, error) {
+	r, err := o.joinText(slice, nil, sep, lastSep)
+	if err != nil {
+		return "", opError("join", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) joinWith(slice any, f any, sep string, lastSep ...string) (string, error) {
+	r, err := o.joinText(slice, f, sep, lastSep)
+	if err != nil {
+		return "", opError("joinWith", f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) joinText(slice any, f any, sep string, lastSep []string) (string, error) {
+	last, err := lastSeparator(sep, lastSep)
+	if err != nil {
+		return "", err
+	}
+	values, err := o.joinValues(slice, f)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return joinStrings(parts, sep, last), nil
+}
+
+// HtmlJoinTemplateFunc is JoinTemplateFunc for html/template. Elements and separators are escaped unless they are
+// already template.HTML, and the result is template.HTML so it is not escaped again.
+func HtmlJoinTemplateFunc(slice any, sep any, lastSep ...any) (ht.HTML, error) {
+	return defaultOperations.htmlJoin(slice, sep, lastSep...)
+}
+
+func HtmlJoinWithTemplateFunc(slice any, f any, sep any, lastSep ...any) (ht.HTML, error) {
+	return defaultOperations.htmlJoinWith(slice, f, sep, lastSep...)
+}
+
+func (o *operations) htmlJoin(slice any, sep any, lastSep ...any) (ht.HTML, error) {
+	r, err := o.joinHTML(slice, nil, sep, lastSep)
+	if err != nil {
+		return "", opError("join", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) htmlJoinWith(slice any, f any, sep any, lastSep ...any) (ht.HTML, error) {
+	r, err := o.joinHTML(slice, f, sep, lastSep)
+	if err != nil {
+		return "", opError("joinWith", f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) joinHTML(slice any, f any, sep any, lastSep []any) (ht.HTML, error) {
+	last, err := lastSeparator(sep, lastSep)
+	if err != nil {
+		return "", err
+	}
+	values, err := o.joinValues(slice, f)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = htmlOf(v)
+	}
+	return ht.HTML(joinStrings(parts, htmlOf(sep), htmlOf(last))), nil
+}
+
+func lastSeparator[T any](sep T, lastSep []T) (T, error) {
+	switch len(lastSep) {
+	case 0:
+		return sep, nil
+	case 1:
+		return lastSep[0], nil
+	}
+	return sep, fmt.Errorf("%w got: %d", ErrExpectedAtMostOneSeparator, len(lastSep))
+}
+
+func (o *operations) joinValues(slice any, f any) ([]any, error) {
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	var c *callback
+	if f != nil {
+		if c, err = o.newCallback(f, 1); err != nil {
+			return nil, err
+		}
+	}
+	values := make([]any, l)
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		ev := av.Index(i)
+		if c != nil {
+			if ev, err = c.call(i, ev); err != nil {
+				return nil, err
+			}
+		}
+		values[i] = indirectInterfaceValue(ev)
+	}
+	return values, nil
+}
+
+func indirectInterfaceValue(v reflect.Value) any {
+	if v = indirectInterface(v); v.IsValid() {
+		return v.Interface()
+	}
+	return nil
+}
+
+func htmlOf(v any) string {
+	if h, ok := v.(ht.HTML); ok {
+		return string(h)
+	}
+	return ht.HTMLEscapeString(fmt.Sprint(v))
+}
+
+func joinStrings(parts []string, sep, last string) string {
+	if len(parts) < 2 {
+		return strings.Join(parts, sep)
+	}
+	return strings.Join(parts[:len(parts)-1], sep) + last + parts[len(parts)-1]
+}
@@ -0,0 +1,185 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	ht "html/template"
+	"testing"
+	"text/template"
+)
+
+func TestJoinTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Join",
+			template:   `{{ join $.Tags ", " }}`,
+			want:       "a, b, c",
+			correctErr: NoError,
+		},
+		{
+			name:       "Join with last separator",
+			template:   `{{ join $.Tags ", " " and " }}`,
+			want:       "a, b and c",
+			correctErr: NoError,
+		},
+		{
+			name:       "Join two with last separator",
+			template:   `{{ join $.Pair ", " " and " }}`,
+			want:       "x and y",
+			correctErr: NoError,
+		},
+		{
+			name:       "Join one",
+			template:   `{{ join $.One ", " " and " }}`,
+			want:       "only",
+			correctErr: NoError,
+		},
+		{
+			name:       "Join nil",
+			template:   `{{ join nil ", " }}`,
+			want:       "",
+			correctErr: NoError,
+		},
+		{
+			name:       "Join numbers",
+			template:   `{{ join $.Numbers "+" }}`,
+			want:       "1+2+3",
+			correctErr: NoError,
+		},
+		{
+			name:       "Join with formatter",
+			template:   `{{ joinWith $.Users $.Funcs.name ", " }}`,
+			want:       "alice, bob",
+			correctErr: NoError,
+		},
+		{
+			name:       "Too many separators",
+			template:   `{{ join $.Tags "," "," "," }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedAtMostOneSeparator),
+		},
+		{
+			name:       "First parameter must be a slice not a string",
+			template:   `{{ join "abc" "," }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, joinTestData())
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("JoinTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("JoinTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestHtmlJoinTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Elements are escaped once",
+			template:   `<p>{{ join $.Unsafe ", " " & " }}</p>`,
+			want:       "<p>&lt;b&gt;, Tom &amp; Jerry &amp; &#34;q&#34;</p>",
+			correctErr: NoError,
+		},
+		{
+			name:       "HTML elements are preserved",
+			template:   `<p>{{ join $.Safe " " }}</p>`,
+			want:       "<p><b>bold</b> &lt;i&gt;</p>",
+			correctErr: NoError,
+		},
+		{
+			name:       "HTML separators are preserved",
+			template:   `<p>{{ join $.Tags $.Break }}</p>`,
+			want:       "<p>a<br>b<br>c</p>",
+			correctErr: NoError,
+		},
+		{
+			name:       "Formatter returning HTML",
+			template:   `<p>{{ joinWith $.Users $.Funcs.link ", " }}</p>`,
+			want:       `<p><a href="/u/1">alice</a>, <a href="/u/2">bob</a></p>`,
+			correctErr: NoError,
+		},
+		{
+			name:       "Formatter returning text",
+			template:   `<p>{{ joinWith $.Users $.Funcs.tag ", " }}</p>`,
+			want:       `<p>&lt;alice&gt;, &lt;bob&gt;</p>`,
+			correctErr: NoError,
+		},
+	}
+	funcs := HtmlFunctions()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := ht.Must(ht.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, joinTestData())
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("HtmlJoinTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("HtmlJoinTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func joinTestData() any {
+	return struct {
+		Tags    []string
+		Pair    []string
+		One     []string
+		Numbers []int
+		Users   []user
+		Unsafe  []string
+		Safe    []any
+		Break   ht.HTML
+		Funcs   map[string]any
+	}{
+		Tags:    []string{"a", "b", "c"},
+		Pair:    []string{"x", "y"},
+		One:     []string{"only"},
+		Numbers: []int{1, 2, 3},
+		Users:   []user{{1, "alice"}, {2, "bob"}},
+		Unsafe:  []string{"<b>", "Tom & Jerry", `"q"`},
+		Safe:    []any{ht.HTML("<b>bold</b>"), "<i>"},
+		Break:   ht.HTML("<br>"),
+		Funcs: map[string]any{
+			"name": func(u user) string { return u.Name },
+			"tag":  func(u user) string { return "<" + u.Name + ">" },
+			"link": func(u user) ht.HTML {
+				return ht.HTML(`<a href="/u/` + string(rune('0'+u.ID)) + `">` + ht.HTMLEscapeString(u.Name) + `</a>`)
+			},
+		},
+	}
+}
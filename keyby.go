@@ -0,0 +1,126 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type DuplicateKeyPolicy string
+
+const (
+	DuplicateKeyFirstWins DuplicateKeyPolicy = "first"
+	DuplicateKeyLastWins  DuplicateKeyPolicy = "last"
+	DuplicateKeyError     DuplicateKeyPolicy = "error"
+)
+
+// KeyByTemplateFunc builds a map from the result of f to the element it was computed from. The optional policy
+// decides what happens when two elements share a key and defaults to DuplicateKeyLastWins.
+func KeyByTemplateFunc(slice any, f any, policy ...DuplicateKeyPolicy) (any, error) {
+	return defaultOperations.keyBy(slice, f, policy...)
+}
+
+// IndexByTemplateFunc is KeyByTemplateFunc under the name indexBy.
+func IndexByTemplateFunc(slice any, f any, policy ...DuplicateKeyPolicy) (any, error) {
+	return defaultOperations.indexBy(slice, f, policy...)
+}
+
+func (o *operations) keyBy(slice any, f any, policy ...DuplicateKeyPolicy) (any, error) {
+	r, err := o.keyMap(slice, f, policy)
+	if err != nil {
+		return nil, opError("keyBy", f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) indexBy(slice any, f any, policy ...DuplicateKeyPolicy) (any, error) {
+	r, err := o.keyMap(slice, f, policy)
+	if err != nil {
+		return nil, opError("indexBy", f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) keyMap(slice any, f any, policy []DuplicateKeyPolicy) (any, error) {
+	p := DuplicateKeyLastWins
+	switch len(policy) {
+	case 0:
+	case 1:
+		p = policy[0]
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedAtMostOnePolicy, len(policy))
+	}
+	switch p {
+	case DuplicateKeyFirstWins, DuplicateKeyLastWins, DuplicateKeyError:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPolicy, p)
+	}
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	c, err := o.newCallback(f, 1)
+	if err != nil {
+		return nil, err
+	}
+	elemType := anyType
+	if av.IsValid() {
+		elemType = av.Type().Elem()
+	}
+
+	keys := make([]reflect.Value, l)
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		if keys[i], err = c.call(i, av.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	keyType := c.out
+	if c.hasErr {
+		keyType = inferType(keys)
+	}
+	if !keyType.Comparable() {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotComparable, keyType)
+	}
+
+	m := reflect.MakeMapWithSize(reflect.MapOf(keyType, elemType), l)
+	for i, k := range keys {
+		if k.Kind() == reflect.Interface && !k.IsNil() && !k.Elem().Comparable() {
+			return nil, elementError(i, av.Index(i), fmt.Errorf("%w: %s", ErrKeyNotComparable, k.Elem().Type()))
+		}
+		if keyType.Kind() == reflect.Interface {
+			kv := reflect.New(keyType).Elem()
+			kv.Set(k)
+			k = kv
+		}
+		if m.MapIndex(k).IsValid() {
+			switch p {
+			case DuplicateKeyFirstWins:
+				continue
+			case DuplicateKeyError:
+				return nil, elementError(i, av.Index(i), fmt.Errorf("%w: %v", ErrDuplicateKey, k))
+			}
+		}
+		m.SetMapIndex(k, av.Index(i))
+	}
+	return m.Interface(), nil
+}
+
+// inferType returns the type shared by all values, or any when they differ, matching how MapTemplateFunc types
+// results of functions which can return an error.
+func inferType(values []reflect.Value) reflect.Type {
+	var t reflect.Type
+	for _, v := range values {
+		rt := v.Type()
+		if t == nil {
+			t = rt
+		} else if rt != t && !rt.AssignableTo(t) {
+			return anyType
+		}
+	}
+	if t == nil {
+		return anyType
+	}
+	return t
+}
@@ -0,0 +1,128 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestKeyByTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Lookup by id",
+			template:   "{{ $byId := keyBy $.Users $.Funcs.id }}{{ (index $byId 2).Name }}",
+			want:       "bob",
+			correctErr: NoError,
+		},
+		{
+			name:       "Key type inferred from the function",
+			template:   "{{ printf \"%T\" (keyBy $.Users $.Funcs.id) }}",
+			want:       "map[int]funtemplates.user",
+			correctErr: NoError,
+		},
+		{
+			name:       "Key type of error returning functions",
+			template:   "{{ printf \"%T\" (keyBy $.Users $.Funcs.name) }}",
+			want:       "map[string]funtemplates.user",
+			correctErr: NoError,
+		},
+		{
+			name:       "indexBy is an alias",
+			template:   "{{ (index (indexBy $.Users $.Funcs.id) 1).Name }}",
+			want:       "alice",
+			correctErr: NoError,
+		},
+		{
+			name:       "Last wins by default",
+			template:   "{{ (index (keyBy $.Duplicates $.Funcs.id) 1).Name }}",
+			want:       "second",
+			correctErr: NoError,
+		},
+		{
+			name:       "First wins",
+			template:   `{{ (index (keyBy $.Duplicates $.Funcs.id "first") 1).Name }}`,
+			want:       "first",
+			correctErr: NoError,
+		},
+		{
+			name:       "Duplicates can be an error",
+			template:   `{{ keyBy $.Duplicates $.Funcs.id "error" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrDuplicateKey),
+		},
+		{
+			name:       "Unknown policy",
+			template:   `{{ keyBy $.Duplicates $.Funcs.id "middle" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrUnknownPolicy),
+		},
+		{
+			name:       "Keys must be comparable",
+			template:   `{{ keyBy $.Users $.Funcs.tags }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrKeyNotComparable),
+		},
+		{
+			name:       "Nil slice",
+			template:   `{{ keyBy nil $.Funcs.id }}`,
+			want:       "map[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Correct error on not a func",
+			template:   `{{ keyBy $.Users 1 }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		Users      []user
+		Duplicates []user
+		Funcs      map[string]any
+	}{
+		Users:      []user{{1, "alice"}, {2, "bob"}},
+		Duplicates: []user{{1, "first"}, {1, "second"}},
+		Funcs: map[string]any{
+			"id":   func(u user) int { return u.ID },
+			"name": func(u user) (string, error) { return u.Name, nil },
+			"tags": func(u user) []string { return nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("KeyByTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("KeyByTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexByReportsItsOwnName(t *testing.T) {
+	_, err := IndexByTemplateFunc([]user{{1, "first"}, {1, "second"}}, func(u user) int { return u.ID }, DuplicateKeyError)
+	var oe *OpError
+	if !errors.As(err, &oe) || oe.Op != "indexBy" || !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected an indexBy OpError wrapping ErrDuplicateKey, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package funtemplates
+
+import (
+	"reflect"
+)
+
+// keySet assigns a stable id to each distinct key in insertion order. Comparable keys are found through a map,
+// anything else, such as slices or maps, falls back to a reflect.DeepEqual scan.
+type keySet struct {
+	ids      map[any]int
+	other    []reflect.Value
+	otherIds []int
+	n        int
+}
+
+func newKeySet() *keySet {
+	return &keySet{ids: map[any]int{}}
+}
+
+// id returns the id of k, adding it if it has not been seen before. The second result reports whether k is new.
+func (s *keySet) id(k reflect.Value) (int, bool) {
+	k = indirectInterface(k)
+	if !k.IsValid() {
+		return s.idOf(nil)
+	}
+	if k.Comparable() {
+		return s.idOf(k.Interface())
+	}
+	for i, o := range s.other {
+		if o.Type() == k.Type() && reflect.DeepEqual(o.Interface(), k.Interface()) {
+			return s.otherIds[i], false
+		}
+	}
+	s.other = append(s.other, k)
+	s.otherIds = append(s.otherIds, s.n)
+	s.n++
+	return s.n - 1, true
+}
+
+func (s *keySet) idOf(k any) (int, bool) {
+	if id, ok := s.ids[k]; ok {
+		return id, false
+	}
+	s.ids[k] = s.n
+	s.n++
+	return s.n - 1, true
+}
@@ -11,7 +11,30 @@ var (
 )
 
 func MapTemplateFunc(slice any, f any) (any, error) {
-	av := reflect.ValueOf(slice)
+	return defaultOperations.mapTemplateFunc(slice, f)
+}
+
+func (o *operations) mapTemplateFunc(slice any, f any) (any, error) {
+	ee := o.elementErrors()
+	r, err := o.mapSlice(slice, f, ee)
+	if err != nil {
+		return nil, opError("map", f, err)
+	}
+	if err := ee.err("map", f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// mapSlice applies f to every element of slice. Elements which fail are handled by ee.
+func (o *operations) mapSlice(slice any, f any, ee *elementErrors) (any, error) {
+	if r, ok, err := o.fastMap(slice, f, ee); ok {
+		return r, err
+	}
+	av, err := o.seqToSlice(reflect.ValueOf(slice))
+	if err != nil {
+		return nil, err
+	}
 	if av.Kind() != reflect.Slice && av.Kind() != reflect.Invalid {
 		return nil, fmt.Errorf("%w not %s", ErrExpectedFirstParameterToBeSlice, av.Kind())
 	}
@@ -20,69 +43,103 @@ func MapTemplateFunc(slice any, f any) (any, error) {
 		return nil, ErrExpected2ndArgumentToBeFunction
 	}
 
-	fvType := fv.Type()
-	numIn := fvType.NumIn()
+	l := 0
+	var elemType reflect.Type
+	if av.Kind() != reflect.Invalid && !av.IsNil() {
+		l = av.Len()
+		elemType = av.Type().Elem()
+	}
+
+	p := planFor(fv.Type(), elemType)
+	numIn := len(p.in)
 	if numIn != 0 && numIn != 1 {
 		return nil, ErrInputFuncMustTake0or1Arguments
 	}
 
-	numOut := fvType.NumOut()
+	numOut := p.numOut
 	if numOut != 1 && numOut != 2 {
 		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, numOut)
 	}
+	if p.outErr != nil {
+		return nil, p.outErr
+	}
 
 	var fvfpt reflect.Type
-	if numIn == 1 {
-		fvfpt = fvType.In(0)
+	if numIn == 1 && !p.elemAssignable {
+		// Only checked per element when the slice's element type is not known to be assignable
+		fvfpt = p.in[0]
 	}
 
-	var fvfrt reflect.Type
-	if numOut == 1 {
-		fvfrt = fvType.Out(0)
-	} else {
-		// numOut == 2
-		fvsrt := fvType.Out(1)
-		if !fvsrt.AssignableTo(errorType) && !fvsrt.Implements(errorType) {
-			return nil, fmt.Errorf("%w instead got: %s", ErrExpectedSecondReturnToBeError, fvsrt)
-		}
-	}
-
-	l := 0
-	if av.Kind() != reflect.Invalid && !av.IsNil() {
-		l = av.Len()
-	}
+	fvfrt := p.out
 
 	// Optimization: Fast path for known single return type
-	if numOut == 1 {
+	if !p.hasErr {
 		// Pre-allocate result slice
 		nra := reflect.MakeSlice(reflect.SliceOf(fvfrt), l, l)
+		// n is the length of the result so far, which trails i when failing elements are skipped
+		n := 0
 
 		if numIn == 1 {
 			// Reuse argument slice to avoid allocation per iteration
 			args := make([]reflect.Value, 1)
 			for i := 0; i < l; i++ {
+				if err := o.checkContext(i); err != nil {
+					return nil, err
+				}
 				ev := av.Index(i)
-				if fvfpt != nil && !ev.Type().AssignableTo(fvfpt) {
-					return nil, fmt.Errorf("item %d not assignable to: %s", i, fvfpt)
+				var r []reflect.Value
+				var elementErr error
+				arg := ev
+				if fvfpt != nil {
+					arg, elementErr = o.assign(i, ev, fvfpt)
+				}
+				if elementErr == nil {
+					args[0] = arg
+					r, elementErr = o.call(fv, p, i, ev, args)
+				}
+				if elementErr != nil {
+					sv, err := ee.handle(i, elementErr, fvfrt)
+					if err != nil {
+						return nil, err
+					}
+					if sv.IsValid() {
+						nra.Index(n).Set(sv)
+						n++
+					}
+					continue
 				}
-				args[0] = ev
-				r := fv.Call(args)
 				// Direct assignment avoiding intermediate reflection overhead
-				nra.Index(i).Set(r[0])
+				nra.Index(n).Set(r[0])
+				n++
 			}
 		} else {
 			// numIn == 0
 			args := []reflect.Value{}
 			for i := 0; i < l; i++ {
-				r := fv.Call(args)
-				nra.Index(i).Set(r[0])
+				if err := o.checkContext(i); err != nil {
+					return nil, err
+				}
+				r, elementErr := o.call(fv, p, i, av.Index(i), args)
+				if elementErr != nil {
+					sv, err := ee.handle(i, elementErr, fvfrt)
+					if err != nil {
+						return nil, err
+					}
+					if sv.IsValid() {
+						nra.Index(n).Set(sv)
+						n++
+					}
+					continue
+				}
+				nra.Index(n).Set(r[0])
+				n++
 			}
 		}
-		return nra.Interface(), nil
+		return nra.Slice(0, n).Interface(), nil
 	}
 
 	// Slow path: Dynamic return type or error handling (numOut == 2)
-	ra := make([]reflect.Value, l)
+	ra := make([]reflect.Value, 0, l)
 	var newType reflect.Type // Initially nil
 
 	// Optimization: Lift loop invariants
@@ -94,39 +151,67 @@ func MapTemplateFunc(slice any, f any) (any, error) {
 	}
 
 	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
 		var r []reflect.Value
+		var elementErr error
 		if numIn == 1 {
 			ev := av.Index(i)
-			if fvfpt != nil && !ev.Type().AssignableTo(fvfpt) {
-				return nil, fmt.Errorf("item %d not assignable to: %s", i, fvfpt)
+			arg := ev
+			if fvfpt != nil {
+				arg, elementErr = o.assign(i, ev, fvfpt)
+			}
+			if elementErr == nil {
+				args[0] = arg
+				r, elementErr = o.call(fv, p, i, ev, args)
 			}
-			args[0] = ev
-			r = fv.Call(args)
 		} else {
-			r = fv.Call(args)
+			r, elementErr = o.call(fv, p, i, av.Index(i), args)
 		}
 
 		// Check for error return (2nd value)
-		if len(r) == 2 && !r[1].IsNil() {
-			return nil, fmt.Errorf("f execution number %d returned: %w", i, r[1].Interface().(error))
+		if elementErr == nil && len(r) == 2 && !r[1].IsNil() {
+			elementErr = callbackError(i, av.Index(i), r[1].Interface().(error))
 		}
-
-		rt := r[0].Type()
-		if newType == nil {
-			newType = rt
-		} else if rt != newType && !rt.AssignableTo(newType) {
-			// Fallback to []interface{} if types are incompatible
-			newType = anyType
+		if elementErr != nil {
+			sv, err := ee.handle(i, elementErr, fvfrt)
+			if err != nil {
+				return nil, err
+			}
+			if !sv.IsValid() {
+				continue
+			}
+			r = []reflect.Value{sv}
 		}
-		ra[i] = r[0]
+
+		newType = widenType(newType, r[0].Type())
+		ra = append(ra, r[0])
 	}
 
-	if newType == nil {
-		newType = anyType
+	return sliceOfValues(newType, ra).Interface(), nil
+}
+
+// widenType returns the element type of a result holding values of types t and rt, t being nil for the first value.
+func widenType(t, rt reflect.Type) reflect.Type {
+	if t == nil {
+		return rt
+	}
+	if rt != t && !rt.AssignableTo(t) {
+		// Fallback to []interface{} if types are incompatible
+		return anyType
+	}
+	return t
+}
+
+// sliceOfValues returns a []t of vs, or a []any if t is nil.
+func sliceOfValues(t reflect.Type, vs []reflect.Value) reflect.Value {
+	if t == nil {
+		t = anyType
 	}
-	nra := reflect.MakeSlice(reflect.SliceOf(newType), l, l)
-	for i, e := range ra {
+	nra := reflect.MakeSlice(reflect.SliceOf(t), len(vs), len(vs))
+	for i, e := range vs {
 		nra.Index(i).Set(e)
 	}
-	return nra.Interface(), nil
+	return nra
 }
@@ -40,3 +40,78 @@ func BenchmarkMapTemplateFunc_InterfaceReturn(b *testing.B) {
 		}
 	}
 }
+
+// The small slice benchmarks are dominated by analysing the callback's signature, which is cached. The Uncached
+// variants clear the cache every iteration for comparison. The data is a named slice type so it takes the reflection
+// path rather than a fast path.
+
+var smallData = ints{1, 2, 3, 4, 5}
+
+func BenchmarkMapTemplateFunc_Small(b *testing.B) {
+	inc := func(i int) int {
+		return i + 1
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := MapTemplateFunc(smallData, inc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapTemplateFunc_SmallUncached(b *testing.B) {
+	inc := func(i int) int {
+		return i + 1
+	}
+	for i := 0; i < b.N; i++ {
+		plans.Clear()
+		if _, err := MapTemplateFunc(smallData, inc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterTemplateFunc_Small(b *testing.B) {
+	odd := func(i int) (bool, error) {
+		return i%2 == 1, nil
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := FilterTemplateFunc(smallData, odd); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterTemplateFunc_SmallUncached(b *testing.B) {
+	odd := func(i int) (bool, error) {
+		return i%2 == 1, nil
+	}
+	for i := 0; i < b.N; i++ {
+		plans.Clear()
+		if _, err := FilterTemplateFunc(smallData, odd); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindIndexTemplateFunc_Small(b *testing.B) {
+	isFive := func(i int) bool {
+		return i == 5
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := FindIndexTemplateFunc(smallData, isFive); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindIndexTemplateFunc_SmallUncached(b *testing.B) {
+	isFive := func(i int) bool {
+		return i == 5
+	}
+	for i := 0; i < b.N; i++ {
+		plans.Clear()
+		if _, err := FindIndexTemplateFunc(smallData, isFive); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
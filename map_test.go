@@ -139,3 +139,5 @@ func NoError(err error) (string, bool) {
 	}
 	return fmt.Sprintf("Expected:\n> %s", "no error"), false
 }
+
+var errTest = errors.New("test error")
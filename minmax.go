@@ -0,0 +1,97 @@
+package funtemplates
+
+import (
+	"reflect"
+)
+
+func MinTemplateFunc(slice any) (any, error) {
+	return defaultOperations.min(slice)
+}
+
+func MaxTemplateFunc(slice any) (any, error) {
+	return defaultOperations.max(slice)
+}
+
+func MinByTemplateFunc(slice any, f any) (any, error) {
+	return defaultOperations.minBy(slice, f)
+}
+
+func MaxByTemplateFunc(slice any, f any) (any, error) {
+	return defaultOperations.maxBy(slice, f)
+}
+
+func (o *operations) min(slice any) (any, error) {
+	return o.extremeOf("min", slice, nil, -1)
+}
+
+func (o *operations) max(slice any) (any, error) {
+	return o.extremeOf("max", slice, nil, 1)
+}
+
+func (o *operations) minBy(slice any, f any) (any, error) {
+	if f == nil {
+		return nil, opError("minBy", f, ErrExpected2ndArgumentToBeFunction)
+	}
+	return o.extremeOf("minBy", slice, f, -1)
+}
+
+func (o *operations) maxBy(slice any, f any) (any, error) {
+	if f == nil {
+		return nil, opError("maxBy", f, ErrExpected2ndArgumentToBeFunction)
+	}
+	return o.extremeOf("maxBy", slice, f, 1)
+}
+
+// extreme returns the first element whose key compares in direction want against every other key. When f is nil
+// the element is its own key.
+func (o *operations) extremeOf(op string, slice any, f any, want int) (any, error) {
+	r, err := o.extreme(slice, f, want)
+	if err != nil {
+		return nil, opError(op, f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) extreme(slice any, f any, want int) (any, error) {
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	var c *callback
+	if f != nil {
+		if c, err = o.newCallback(f, 1); err != nil {
+			return nil, err
+		}
+	}
+	if l == 0 {
+		return nil, ErrEmptySlice
+	}
+	best := -1
+	var bestKey reflect.Value
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		key := av.Index(i)
+		if c != nil {
+			if key, err = c.call(i, key); err != nil {
+				return nil, err
+			}
+		}
+		if best == -1 {
+			if _, err := compareValues(key, key); err != nil {
+				return nil, elementError(i, av.Index(i), err)
+			}
+			best, bestKey = i, key
+			continue
+		}
+		r, err := compareValues(key, bestKey)
+		if err != nil {
+			return nil, elementError(i, av.Index(i), err)
+		}
+		if r == want {
+			best, bestKey = i, key
+		}
+	}
+	return av.Index(best).Interface(), nil
+}
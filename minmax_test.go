@@ -0,0 +1,152 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+	"time"
+)
+
+type product struct {
+	Name  string
+	Price float64
+}
+
+func TestMinMaxTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Min of ints",
+			template:   "{{ min $.DataInts }}",
+			want:       "-2",
+			correctErr: NoError,
+		},
+		{
+			name:       "Max of ints",
+			template:   "{{ max $.DataInts }}",
+			want:       "7",
+			correctErr: NoError,
+		},
+		{
+			name:       "Min of strings",
+			template:   "{{ min $.DataStrings }}",
+			want:       "apple",
+			correctErr: NoError,
+		},
+		{
+			name:       "Max of times",
+			template:   `{{ (max $.DataTimes).Format "2006-01-02" }}`,
+			want:       "2024-03-01",
+			correctErr: NoError,
+		},
+		{
+			name:       "Mixed numbers compare by value",
+			template:   "{{ min $.DataMixed }} {{ max $.DataMixed }}",
+			want:       "-1 2.5",
+			correctErr: NoError,
+		},
+		{
+			name:       "Mixed strings and numbers cannot be compared",
+			template:   "{{ min $.DataIncomparable }}",
+			want:       "",
+			correctErr: ErrorIs(ErrIncomparableTypes),
+		},
+		{
+			name:       "Structs cannot be compared",
+			template:   "{{ min $.Products }}",
+			want:       "",
+			correctErr: ErrorIs(ErrIncomparableTypes),
+		},
+		{
+			name:       "Empty slice",
+			template:   "{{ max $.Empty }}",
+			want:       "",
+			correctErr: ErrorIs(ErrEmptySlice),
+		},
+		{
+			name:       "Nil slice",
+			template:   "{{ max nil }}",
+			want:       "",
+			correctErr: ErrorIs(ErrEmptySlice),
+		},
+		{
+			name:       "MinBy returns the element",
+			template:   "{{ (minBy $.Products $.Funcs.price).Name }}",
+			want:       "pen",
+			correctErr: NoError,
+		},
+		{
+			name:       "MaxBy keeps the first of equal keys",
+			template:   "{{ (maxBy $.Products $.Funcs.price).Name }}",
+			want:       "book",
+			correctErr: NoError,
+		},
+		{
+			name:       "MaxBy callback error",
+			template:   "{{ maxBy $.Products $.Funcs.fail }}",
+			want:       "",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:       "MinBy requires a function",
+			template:   "{{ minBy $.Products 1 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+		{
+			name:       "First parameter must be a slice not a number",
+			template:   "{{ min 123 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		DataInts         []int
+		DataStrings      []string
+		DataTimes        []time.Time
+		DataMixed        []any
+		DataIncomparable []any
+		Products         []product
+		Empty            []int
+		Funcs            map[string]any
+	}{
+		DataInts:         []int{3, -2, 7, 0},
+		DataStrings:      []string{"pear", "apple", "zucchini"},
+		DataTimes:        []time.Time{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		DataMixed:        []any{1, uint8(2), 2.5, int64(-1)},
+		DataIncomparable: []any{1, "one"},
+		Products:         []product{{"book", 12}, {"pen", 1.5}, {"lamp", 12}},
+		Empty:            []int{},
+		Funcs: map[string]any{
+			"price": func(p product) float64 { return p.Price },
+			"fail":  func(p product) (float64, error) { return 0, errTest },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("MinTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("MinTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package funtemplates
+
+import (
+	"fmt"
+)
+
+func FirstTemplateFunc(slice any, def ...any) (any, error) {
+	return defaultOperations.first(slice, def...)
+}
+
+func LastTemplateFunc(slice any, def ...any) (any, error) {
+	return defaultOperations.last(slice, def...)
+}
+
+// NthTemplateFunc returns the element at index n, counting from the end of the slice when n is negative. When n is
+// out of range the optional default is returned instead, or nil if none was given.
+func NthTemplateFunc(slice any, n int, def ...any) (any, error) {
+	return defaultOperations.nth(slice, n, def...)
+}
+
+func (o *operations) first(slice any, def ...any) (any, error) {
+	r, err := o.element(slice, 0, def)
+	if err != nil {
+		return nil, opError("first", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) last(slice any, def ...any) (any, error) {
+	r, err := o.element(slice, -1, def)
+	if err != nil {
+		return nil, opError("last", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) nth(slice any, n int, def ...any) (any, error) {
+	r, err := o.element(slice, n, def)
+	if err != nil {
+		return nil, opError("nth", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) element(slice any, n int, def []any) (any, error) {
+	if len(def) > 1 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedAtMostOneDefault, len(def))
+	}
+	if err := o.checkContext(0); err != nil {
+		return nil, err
+	}
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		n += l
+	}
+	if n < 0 || n >= l {
+		if len(def) == 1 {
+			return def[0], nil
+		}
+		return nil, nil
+	}
+	return av.Index(n).Interface(), nil
+}
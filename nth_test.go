@@ -0,0 +1,115 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestNthTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "first",
+			template:   "{{ first $.DataInts }}",
+			want:       "1",
+			correctErr: NoError,
+		},
+		{
+			name:       "last",
+			template:   "{{ last $.DataInts }}",
+			want:       "4",
+			correctErr: NoError,
+		},
+		{
+			name:       "nth positive",
+			template:   "{{ nth $.DataInts 2 }}",
+			want:       "3",
+			correctErr: NoError,
+		},
+		{
+			name:       "nth negative",
+			template:   "{{ nth $.DataInts -2 }}",
+			want:       "3",
+			correctErr: NoError,
+		},
+		{
+			name:       "nth out of range without default",
+			template:   "{{ nth $.DataInts 10 }}",
+			want:       "<no value>",
+			correctErr: NoError,
+		},
+		{
+			name:       "nth out of range with default",
+			template:   `{{ nth $.DataInts -10 "none" }}`,
+			want:       "none",
+			correctErr: NoError,
+		},
+		{
+			name:       "first of empty with default",
+			template:   `{{ first $.Empty "none" }}`,
+			want:       "none",
+			correctErr: NoError,
+		},
+		{
+			name:       "last of nil with default",
+			template:   `{{ last nil 0 }}`,
+			want:       "0",
+			correctErr: NoError,
+		},
+		{
+			name:       "nil element is not replaced by default",
+			template:   `{{ first $.DataPtrs "none" }}`,
+			want:       "<nil>",
+			correctErr: NoError,
+		},
+		{
+			name:       "Too many defaults",
+			template:   `{{ first $.DataInts 1 2 }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedAtMostOneDefault),
+		},
+		{
+			name:       "First parameter must be a slice not a string",
+			template:   `{{ first "asdfasdf" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		DataInts []int
+		DataPtrs []*int
+		Empty    []int
+	}{
+		DataInts: []int{1, 2, 3, 4},
+		DataPtrs: []*int{nil},
+		Empty:    []int{},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("NthTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("NthTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package funtemplates
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"reflect"
+)
+
+var (
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+	bigIntType     = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType   = reflect.TypeOf((*big.Float)(nil))
+	bigRatType     = reflect.TypeOf((*big.Rat)(nil))
+)
+
+type numberKind int
+
+const (
+	numInt numberKind = iota
+	numUint
+	numFloat
+	numBigInt
+	numBigRat
+	numBigFloat
+)
+
+// number is a numeric value promoted into the widest representation of its family. Only the field matching kind is
+// set.
+type number struct {
+	kind numberKind
+	i    int64
+	u    uint64
+	f    float64
+	bi   *big.Int
+	br   *big.Rat
+	bf   *big.Float
+}
+
+func numberOf(v reflect.Value) (number, error) {
+	v = indirectInterface(v)
+	if !v.IsValid() {
+		return number{}, fmt.Errorf("%w: nil", ErrNotANumber)
+	}
+	switch v.Type() {
+	case jsonNumberType:
+		n := v.Interface().(json.Number)
+		if i, err := n.Int64(); err == nil {
+			return number{kind: numInt, i: i}, nil
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return number{}, fmt.Errorf("%w: %q", ErrNotANumber, n)
+		}
+		return number{kind: numFloat, f: f}, nil
+	case bigIntType:
+		if v.IsNil() {
+			return number{}, fmt.Errorf("%w: nil %s", ErrNotANumber, v.Type())
+		}
+		return number{kind: numBigInt, bi: v.Interface().(*big.Int)}, nil
+	case bigRatType:
+		if v.IsNil() {
+			return number{}, fmt.Errorf("%w: nil %s", ErrNotANumber, v.Type())
+		}
+		return number{kind: numBigRat, br: v.Interface().(*big.Rat)}, nil
+	case bigFloatType:
+		if v.IsNil() {
+			return number{}, fmt.Errorf("%w: nil %s", ErrNotANumber, v.Type())
+		}
+		return number{kind: numBigFloat, bf: v.Interface().(*big.Float)}, nil
+	}
+	switch orderClassOf(v) {
+	case orderInt:
+		return number{kind: numInt, i: v.Int()}, nil
+	case orderUint:
+		return number{kind: numUint, u: v.Uint()}, nil
+	case orderFloat:
+		return number{kind: numFloat, f: v.Float()}, nil
+	}
+	return number{}, fmt.Errorf("%w: %s", ErrNotANumber, v.Type())
+}
+
+// promotedKind is the representation able to hold both a and b.
+func promotedKind(a, b numberKind) numberKind {
+	switch {
+	case a == b:
+		return a
+	case a == numBigFloat || b == numBigFloat:
+		return numBigFloat
+	case a == numBigRat || b == numBigRat:
+		return numBigRat
+	case a == numBigInt || b == numBigInt:
+		if a == numFloat || b == numFloat {
+			return numBigFloat
+		}
+		return numBigInt
+	case a == numFloat || b == numFloat:
+		return numFloat
+	}
+	return numInt
+}
+
+func (n number) as(kind numberKind) (number, error) {
+	if n.kind == kind {
+		return n, nil
+	}
+	switch kind {
+	case numInt:
+		if n.u > math.MaxInt64 {
+			return number{}, fmt.Errorf("%w: %d does not fit in int64", ErrIntegerOverflow, n.u)
+		}
+		return number{kind: numInt, i: int64(n.u)}, nil
+	case numFloat:
+		return number{kind: numFloat, f: n.float()}, nil
+	case numBigInt:
+		return number{kind: numBigInt, bi: n.bigInt()}, nil
+	case numBigRat:
+		r := new(big.Rat)
+		switch n.kind {
+		case numFloat:
+			if r.SetFloat64(n.f) == nil {
+				return number{}, fmt.Errorf("%w: %v", ErrNotANumber, n.f)
+			}
+		case numBigInt:
+			r.SetInt(n.bi)
+		default:
+			r.SetInt(n.bigInt())
+		}
+		return number{kind: numBigRat, br: r}, nil
+	case numBigFloat:
+		f := new(big.Float)
+		switch n.kind {
+		case numFloat:
+			if math.IsNaN(n.f) || math.IsInf(n.f, 0) {
+				return number{}, fmt.Errorf("%w: %v", ErrNotANumber, n.f)
+			}
+			f.SetFloat64(n.f)
+		case numBigInt:
+			f.SetInt(n.bi)
+		case numBigRat:
+			f.SetRat(n.br)
+		default:
+			f.SetInt(n.bigInt())
+		}
+		return number{kind: numBigFloat, bf: f}, nil
+	}
+	return number{}, fmt.Errorf("%w: cannot convert", ErrNotANumber)
+}
+
+func (n number) bigInt() *big.Int {
+	switch n.kind {
+	case numUint:
+		return new(big.Int).SetUint64(n.u)
+	case numBigInt:
+		return n.bi
+	}
+	return big.NewInt(n.i)
+}
+
+// convert returns n as a value of t, an integer, unsigned or float type. Integers only take whole numbers they can
+// hold, floats take the nearest value.
+func (n number) convert(t reflect.Type) (reflect.Value, bool) {
+	r := reflect.New(t).Elem()
+	switch orderClassOf(r) {
+	case orderInt:
+		i, ok := n.wholeNumber()
+		if !ok || !i.IsInt64() || r.OverflowInt(i.Int64()) {
+			return reflect.Value{}, false
+		}
+		r.SetInt(i.Int64())
+	case orderUint:
+		i, ok := n.wholeNumber()
+		if !ok || !i.IsUint64() || r.OverflowUint(i.Uint64()) {
+			return reflect.Value{}, false
+		}
+		r.SetUint(i.Uint64())
+	case orderFloat:
+		if f := n.float(); !r.OverflowFloat(f) {
+			r.SetFloat(f)
+		} else {
+			return reflect.Value{}, false
+		}
+	default:
+		return reflect.Value{}, false
+	}
+	return r, true
+}
+
+// wholeNumber returns n as a big.Int when it has no fractional part.
+func (n number) wholeNumber() (*big.Int, bool) {
+	switch n.kind {
+	case numFloat:
+		if math.IsInf(n.f, 0) || n.f != math.Trunc(n.f) {
+			return nil, false
+		}
+		i, _ := big.NewFloat(n.f).Int(nil)
+		return i, true
+	case numBigRat:
+		if !n.br.IsInt() {
+			return nil, false
+		}
+		return n.br.Num(), true
+	case numBigFloat:
+		if !n.bf.IsInt() {
+			return nil, false
+		}
+		i, _ := n.bf.Int(nil)
+		return i, true
+	}
+	return n.bigInt(), true
+}
+
+func (n number) float() float64 {
+	switch n.kind {
+	case numInt:
+		return float64(n.i)
+	case numUint:
+		return float64(n.u)
+	case numBigInt:
+		f, _ := new(big.Float).SetInt(n.bi).Float64()
+		return f
+	case numBigRat:
+		f, _ := n.br.Float64()
+		return f
+	case numBigFloat:
+		f, _ := n.bf.Float64()
+		return f
+	}
+	return n.f
+}
+
+// combine applies op to a and b after promoting them to a common representation. Integer results which do not fit
+// in 64 bits return ErrIntegerOverflow.
+func combine(a, b number, op byte) (number, error) {
+	kind := promotedKind(a.kind, b.kind)
+	a, err := a.as(kind)
+	if err != nil {
+		return number{}, err
+	}
+	if b, err = b.as(kind); err != nil {
+		return number{}, err
+	}
+	switch kind {
+	case numInt:
+		r := new(big.Int)
+		if op == '+' {
+			r.Add(big.NewInt(a.i), big.NewInt(b.i))
+		} else {
+			r.Mul(big.NewInt(a.i), big.NewInt(b.i))
+		}
+		if !r.IsInt64() {
+			return number{}, fmt.Errorf("%w: %d %c %d", ErrIntegerOverflow, a.i, op, b.i)
+		}
+		return number{kind: numInt, i: r.Int64()}, nil
+	case numUint:
+		var hi, lo uint64
+		if op == '+' {
+			lo, hi = bits.Add64(a.u, b.u, 0)
+		} else {
+			hi, lo = bits.Mul64(a.u, b.u)
+		}
+		if hi != 0 {
+			return number{}, fmt.Errorf("%w: %d %c %d", ErrIntegerOverflow, a.u, op, b.u)
+		}
+		return number{kind: numUint, u: lo}, nil
+	case numFloat:
+		if op == '+' {
+			return number{kind: numFloat, f: a.f + b.f}, nil
+		}
+		return number{kind: numFloat, f: a.f * b.f}, nil
+	case numBigInt:
+		if op == '+' {
+			return number{kind: numBigInt, bi: new(big.Int).Add(a.bi, b.bi)}, nil
+		}
+		return number{kind: numBigInt, bi: new(big.Int).Mul(a.bi, b.bi)}, nil
+	case numBigRat:
+		if op == '+' {
+			return number{kind: numBigRat, br: new(big.Rat).Add(a.br, b.br)}, nil
+		}
+		return number{kind: numBigRat, br: new(big.Rat).Mul(a.br, b.br)}, nil
+	}
+	bf, err := bigFloatOp(a.bf, b.bf, op)
+	if err != nil {
+		return number{}, err
+	}
+	return number{kind: numBigFloat, bf: bf}, nil
+}
+
+// bigFloatOp applies op to a and b, returning ErrNotANumber rather than panicking when the result is not a number,
+// as for +Inf added to -Inf or zero multiplied by an infinity.
+func bigFloatOp(a, b *big.Float, op byte) (r *big.Float, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			nan, ok := v.(big.ErrNaN)
+			if !ok {
+				panic(v)
+			}
+			r, err = nil, fmt.Errorf("%w: %v %c %v: %v", ErrNotANumber, a, op, b, nan)
+		}
+	}()
+	if op == '+' {
+		return new(big.Float).Add(a, b), nil
+	}
+	return new(big.Float).Mul(a, b), nil
+}
+
+// value returns n as t when t is a concrete integer, unsigned or float type, otherwise as its natural Go type.
+func (n number) value(t reflect.Type) (any, error) {
+	if t != nil {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n.kind == numInt {
+				r := reflect.New(t).Elem()
+				if r.OverflowInt(n.i) {
+					return nil, fmt.Errorf("%w: %d does not fit in %s", ErrIntegerOverflow, n.i, t)
+				}
+				r.SetInt(n.i)
+				return r.Interface(), nil
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if n.kind == numUint {
+				r := reflect.New(t).Elem()
+				if r.OverflowUint(n.u) {
+					return nil, fmt.Errorf("%w: %d does not fit in %s", ErrIntegerOverflow, n.u, t)
+				}
+				r.SetUint(n.u)
+				return r.Interface(), nil
+			}
+		case reflect.Float32, reflect.Float64:
+			if n.kind == numFloat {
+				return reflect.ValueOf(n.f).Convert(t).Interface(), nil
+			}
+		}
+	}
+	switch n.kind {
+	case numInt:
+		if n.i < math.MinInt || n.i > math.MaxInt {
+			return n.i, nil
+		}
+		return int(n.i), nil
+	case numUint:
+		return n.u, nil
+	case numFloat:
+		return n.f, nil
+	case numBigInt:
+		return n.bi, nil
+	case numBigRat:
+		return n.br, nil
+	}
+	return n.bf, nil
+}
@@ -0,0 +1,101 @@
+package funtemplates
+
+import (
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Observer is told when each operation starts and ends, for logging, metrics or tracing. Its methods are called on
+// the goroutine executing the template, and concurrently when templates are executed concurrently.
+type Observer interface {
+	// Start is called before the operation runs.
+	Start(op *Operation)
+	// End is called after the operation returns, with how long it took and the error it returned.
+	End(op *Operation, d time.Duration, err error)
+}
+
+// CallbackObserver is an Observer which is also told about each callback call. Callbacks are called between the
+// Start and End of their operation, except for those of lazyMap and lazyFilter which are called when the stream is
+// evaluated, and concurrently for pmap and pfilter.
+type CallbackObserver interface {
+	Observer
+	// Callback is called after each callback call with the index of the element, how long it took and the error
+	// it returned or panicked with.
+	Callback(f reflect.Type, i int, d time.Duration, err error)
+}
+
+// Operation describes one call of an operation.
+type Operation struct {
+	// Name is the name of the operation, such as "map", whatever it is registered as
+	Name string
+	// Input is the number of elements given, -1 when they are not a slice
+	Input int
+	// Callback is the type of the function given, nil if there is none
+	Callback reflect.Type
+	// Value is for the observer, for example to keep a span between Start and End
+	Value any
+}
+
+// WithObserver reports the operations to obs. When it is a CallbackObserver the callbacks are reported too, and the
+// reflection free fast paths are not taken so every callback is reported with its type.
+func WithObserver(obs Observer) Option {
+	return func(o *operations) {
+		o.observer = obs
+		o.callbackObserver, _ = obs.(CallbackObserver)
+	}
+}
+
+// observed returns fn, the function of operation op, reporting its calls to the observer.
+func (o *operations) observed(op string, fn any) any {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		info := operationOf(op, ft, args)
+		o.observer.Start(info)
+		start := time.Now()
+		var r []reflect.Value
+		if ft.IsVariadic() {
+			r = fv.CallSlice(args)
+		} else {
+			r = fv.Call(args)
+		}
+		var err error
+		if last := r[len(r)-1]; last.Type() == errorType && !last.IsNil() {
+			err = last.Interface().(error)
+		}
+		o.observer.End(info, time.Since(start), err)
+		return r
+	}).Interface()
+}
+
+// operationOf describes the call of op, of type ft, with args. The first argument is the input and the first
+// function the callback.
+func operationOf(op string, ft reflect.Type, args []reflect.Value) *Operation {
+	info := &Operation{Name: op, Input: -1}
+	values := args
+	if ft.IsVariadic() {
+		values = slices.Clone(args[:len(args)-1])
+		variadic := args[len(args)-1]
+		for i := 0; i < variadic.Len(); i++ {
+			values = append(values, variadic.Index(i))
+		}
+	}
+	for n, a := range values {
+		a = indirectInterface(a)
+		switch {
+		case n == 0 && !a.IsValid():
+			info.Input = 0
+		case n == 0 && a.Kind() == reflect.Slice:
+			info.Input = a.Len()
+		case info.Callback == nil && a.Kind() == reflect.Func && !a.Type().CanSeq():
+			info.Callback = a.Type()
+		}
+	}
+	return info
+}
+
+// observeCallback reports a call of a callback of type f on element i, which started at start.
+func (o *operations) observeCallback(f reflect.Type, i int, start time.Time, err error) {
+	o.callbackObserver.Callback(f, i, time.Since(start), err)
+}
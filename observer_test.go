@@ -0,0 +1,170 @@
+package funtemplates
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+// recorder records what it observes as lines of text.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Start(op *Operation) {
+	op.Value = "span"
+	r.record("start %s %d %v", op.Name, op.Input, op.Callback)
+}
+
+func (r *recorder) End(op *Operation, d time.Duration, err error) {
+	r.record("end %s %v %v", op.Name, op.Value, err != nil)
+}
+
+type callbackRecorder struct {
+	recorder
+}
+
+func (r *callbackRecorder) Callback(f reflect.Type, i int, d time.Duration, err error) {
+	r.record("call %v %d %v", f, i, err != nil)
+}
+
+func TestWithObserver(t *testing.T) {
+	tests := []struct {
+		name      string
+		callbacks bool
+		template  string
+		want      []string
+	}{
+		{
+			name:     "Operations",
+			template: "{{ map $.Data $.Inc }} {{ sum $.Data }} {{ seq 3 }}",
+			want: []string{
+				"start map 3 func(int) int",
+				"end map span false",
+				"start sum 3 <nil>",
+				"end sum span false",
+				"start seq -1 <nil>",
+				"end seq span false",
+			},
+		},
+		{
+			name:     "Variadic operations",
+			template: "{{ union $.Data $.Data $.Inc }}",
+			want: []string{
+				"start union 3 func(int) int",
+				"end union span false",
+			},
+		},
+		{
+			name:     "Nested operations",
+			template: "{{ take (lazyMap (seqIter 5) $.Inc) 2 }}",
+			want: []string{
+				"start seqIter -1 <nil>",
+				"end seqIter span false",
+				"start lazyMap -1 func(int) int",
+				"end lazyMap span false",
+				"start take -1 <nil>",
+				"end take span false",
+			},
+		},
+		{
+			name:     "Errors",
+			template: "{{ map $.Data $.Fail }}",
+			want: []string{
+				"start map 3 func(int) (int, error)",
+				"end map span true",
+			},
+		},
+		{
+			name:      "Callbacks",
+			callbacks: true,
+			template:  "{{ map $.Data $.Inc }} {{ map $.Data $.Fail }}",
+			want: []string{
+				"start map 3 func(int) int",
+				"call func(int) int 0 false",
+				"call func(int) int 1 false",
+				"call func(int) int 2 false",
+				"end map span false",
+				"start map 3 func(int) (int, error)",
+				"call func(int) (int, error) 0 false",
+				"call func(int) (int, error) 1 true",
+				"end map span true",
+			},
+		},
+	}
+	data := map[string]any{
+		"Data": []int{1, 2, 3},
+		"Inc":  func(i int) int { return i + 1 },
+		"Fail": func(i int) (int, error) {
+			if i == 2 {
+				return 0, errTest
+			}
+			return i, nil
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &callbackRecorder{}
+			var obs Observer = &cr.recorder
+			if tt.callbacks {
+				obs = cr
+			}
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(WithObserver(obs))).Parse(tt.template))
+			_ = tmpl.Execute(bytes.NewBuffer(nil), data)
+			if diff := cmp.Diff(tt.want, cr.events); diff != "" {
+				t.Errorf("observed diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestObservedFunctionsReturnTheSameResults(t *testing.T) {
+	text := `{{ map $.Data $.Inc }} {{ filter $.Data $.Odd }} {{ join $.Data ", " }} {{ union $.Data (seq 5) }} {{ scan $.Data 10 $.Add }}`
+	data := map[string]any{
+		"Data": []int{1, 2, 3},
+		"Inc":  func(i int) int { return i + 1 },
+		"Odd":  func(i int) bool { return i%2 == 1 },
+		"Add":  func(a, b int) int { return a + b },
+	}
+	var results []string
+	for _, funcs := range []map[string]any{TextFunctions(), TextFunctions(WithObserver(&callbackRecorder{}))} {
+		got := strings.Builder{}
+		tmpl := template.Must(template.New("").Funcs(funcs).Parse(text))
+		if err := tmpl.Execute(&got, data); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, got.String())
+	}
+	if diff := cmp.Diff(results[0], results[1]); diff != "" {
+		t.Errorf("observed results diff =\n %s", diff)
+	}
+}
+
+func TestCallbackObserverWithRepanic(t *testing.T) {
+	cr := &callbackRecorder{}
+	o := newOperations(WithRepanic(true), WithObserver(cr))
+	defer func() {
+		if _, ok := recover().(*PanicError); !ok {
+			t.Errorf("expected a panic with a *PanicError")
+		}
+		want := []string{"call func(int) (int, error) 0 true"}
+		if diff := cmp.Diff(want, cr.events); diff != "" {
+			t.Errorf("observed diff =\n %s", diff)
+		}
+	}()
+	_, _ = o.mapTemplateFunc([]int{1}, func(int) (int, error) { panic("boom") })
+}
@@ -0,0 +1,99 @@
+package funtemplates
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Option configures the functions returned by New, TextFunctions and HtmlFunctions.
+type Option func(*operations)
+
+// operations holds the configuration shared by the functions of one Functions. The package level XxxTemplateFunc
+// functions use defaultOperations.
+type operations struct {
+	errorPolicy ErrorPolicy
+	substitute  any
+	repanic     bool
+	workers     int
+	ctx         context.Context
+	budget      Budget
+	coercion    Coercion
+	observer    Observer
+	// callbackObserver is observer when it is also a CallbackObserver
+	callbackObserver CallbackObserver
+	// The naming of the operations, see New
+	prefix  string
+	include map[string]bool
+	exclude map[string]bool
+	renames map[string]string
+	// calls counts the callback calls of one execution against the budget, nil outside Functions.ForExecution
+	calls *atomic.Int64
+}
+
+var defaultOperations = newOperations()
+
+func newOperations(opts ...Option) *operations {
+	o := &operations{
+		errorPolicy: ErrorPolicyFailFast,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithErrorPolicy sets what map and filter do when an element fails. The default is ErrorPolicyFailFast.
+func WithErrorPolicy(p ErrorPolicy) Option {
+	return func(o *operations) {
+		o.errorPolicy = p
+	}
+}
+
+// WithSubstitute selects ErrorPolicySubstitute, using v in place of the result for failing elements.
+func WithSubstitute(v any) Option {
+	return func(o *operations) {
+		o.errorPolicy = ErrorPolicySubstitute
+		o.substitute = v
+	}
+}
+
+// WithPrefix adds prefix to the name of every operation, so "fp" registers map as fpmap and "fp_" as fp_map.
+func WithPrefix(prefix string) Option {
+	return func(o *operations) {
+		o.prefix = prefix
+	}
+}
+
+// WithInclude registers only the named operations, and any named by other WithInclude options.
+func WithInclude(ops ...string) Option {
+	return func(o *operations) {
+		if o.include == nil {
+			o.include = map[string]bool{}
+		}
+		for _, op := range ops {
+			o.include[op] = true
+		}
+	}
+}
+
+// WithExclude leaves the named operations out.
+func WithExclude(ops ...string) Option {
+	return func(o *operations) {
+		if o.exclude == nil {
+			o.exclude = map[string]bool{}
+		}
+		for _, op := range ops {
+			o.exclude[op] = true
+		}
+	}
+}
+
+// WithRename registers op as name instead. The prefix is still added.
+func WithRename(op, name string) Option {
+	return func(o *operations) {
+		if o.renames == nil {
+			o.renames = map[string]string{}
+		}
+		o.renames[op] = name
+	}
+}
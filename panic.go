@@ -0,0 +1,79 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"time"
+)
+
+// PanicError is the cause of the OpError returned when a callback panics. It unwraps to ErrCallbackPanicked and, when
+// the callback panicked with an error, to that error.
+type PanicError struct {
+	Index int
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrCallbackPanicked, e.Value)
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrCallbackPanicked, err}
+	}
+	return []error{ErrCallbackPanicked}
+}
+
+// WithRepanic makes callbacks which panic panic again with a *PanicError, for debugging, rather than failing the
+// operation.
+func WithRepanic(repanic bool) Option {
+	return func(o *operations) {
+		o.repanic = repanic
+	}
+}
+
+// call invokes fv, whose plan is p, for element i, turning a panic into an element error. The bound context is
+// passed first when fv takes one.
+func (o *operations) call(fv reflect.Value, p *plan, i int, element reflect.Value, args []reflect.Value) (r []reflect.Value, err error) {
+	if o.callbackObserver != nil {
+		start := time.Now()
+		defer func() {
+			// With WithRepanic the callback's panic is on its way up as a *PanicError, and there are no results
+			v := recover()
+			cerr := err
+			if pe, ok := v.(*PanicError); ok {
+				cerr = pe
+			} else if cerr == nil && p.hasErr && len(r) == 2 && !r[1].IsNil() {
+				cerr = r[1].Interface().(error)
+			}
+			o.observeCallback(fv.Type(), i, start, cerr)
+			if v != nil {
+				panic(v)
+			}
+		}()
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			r, err = nil, o.panicked(i, element, v)
+		}
+	}()
+	if err := o.countCall(); err != nil {
+		return nil, err
+	}
+	if p.ctx {
+		args = append([]reflect.Value{reflect.ValueOf(o.context())}, args...)
+	}
+	return fv.Call(args), nil
+}
+
+// panicked returns the error for a callback which panicked with v on element i. It must be called by the deferred
+// function which recovered v, so the stack is that of the panic.
+func (o *operations) panicked(i int, element reflect.Value, v any) error {
+	pe := &PanicError{Index: i, Value: v, Stack: debug.Stack()}
+	if o.repanic {
+		panic(pe)
+	}
+	return elementError(i, element, pe)
+}
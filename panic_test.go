@@ -0,0 +1,125 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestCallbackPanics(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "map",
+			template:   "{{ map $.Data $.Deref }}",
+			correctErr: ErrorIs(ErrCallbackPanicked),
+		},
+		{
+			name:       "map without parameters",
+			template:   "{{ map $.Data $.Panic }}",
+			correctErr: ErrorIs(ErrCallbackPanicked),
+		},
+		{
+			name:       "filter",
+			template:   "{{ filter $.Data $.DerefOk }}",
+			correctErr: ErrorIs(ErrCallbackPanicked),
+		},
+		{
+			name:       "find",
+			template:   "{{ find $.Data $.DerefOk }}",
+			correctErr: ErrorIs(ErrCallbackPanicked),
+		},
+		{
+			name:       "findIndex",
+			template:   "{{ findIndex $.Data $.DerefOk }}",
+			correctErr: ErrorIs(ErrCallbackPanicked),
+		},
+		{
+			name:       "Panicking with an error",
+			template:   "{{ map $.Data $.PanicErr }}",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:       "Panics follow the error policy",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ map $.Data $.Deref }}",
+			want:       "[1 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry reports panics",
+			template:   "{{ $r := mapTry $.Data $.Deref }}{{ $r.Failed }}",
+			want:       "[1]",
+			correctErr: NoError,
+		},
+	}
+	data := map[string]any{
+		"Data":     []*int{ptr(1), nil, ptr(3)},
+		"Deref":    func(i *int) int { return *i },
+		"DerefOk":  func(i *int) bool { return *i > 1 },
+		"Panic":    func() int { panic("no") },
+		"PanicErr": func(i *int) int { panic(errTest) },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(tt.opts...)).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("callback panic got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("callback panic diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	_, err := MapTemplateFunc([]*int{ptr(1), nil}, func(i *int) int { return *i })
+	var oe *OpError
+	var pe *PanicError
+	if !errors.As(err, &oe) || !errors.As(err, &pe) {
+		t.Fatalf("expected an *OpError wrapping a *PanicError, got %T: %v", err, err)
+	}
+	if oe.Op != "map" || oe.Index != 1 || pe.Index != 1 {
+		t.Errorf("got op %q index %d panic index %d, want map 1 1", oe.Op, oe.Index, pe.Index)
+	}
+	if !strings.Contains(string(pe.Stack), "TestPanicError") {
+		t.Errorf("expected the stack to include the callback, got:\n%s", pe.Stack)
+	}
+	if got, want := err.Error(), "map: item 1: f panicked: runtime error: invalid memory address or nil pointer dereference"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWithRepanic(t *testing.T) {
+	mapFunc := TextFunctions(WithRepanic(true))["map"].(func(any, any) (any, error))
+	defer func() {
+		pe, ok := recover().(*PanicError)
+		if !ok || pe.Index != 0 || pe.Value != "no" {
+			t.Errorf("expected a *PanicError for item 0, got %v", pe)
+		}
+	}()
+	_, _ = mapFunc([]int{1}, func(i int) int { panic("no") })
+	t.Error("expected map to panic")
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,308 @@
+package funtemplates
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WithWorkers sets the number of goroutines pmap and pfilter call their function on. The default is GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(o *operations) {
+		o.workers = n
+	}
+}
+
+func PMapTemplateFunc(slice any, f any) (any, error) {
+	return defaultOperations.pmap(slice, f)
+}
+
+func PFilterTemplateFunc(slice any, f any) (any, error) {
+	return defaultOperations.pfilter(slice, f)
+}
+
+// pmap is map calling f on several goroutines. The result is in the order of slice and has the same type map would
+// return.
+func (o *operations) pmap(slice any, f any) (any, error) {
+	ee := o.elementErrors()
+	r, err := o.pmapSlice(slice, f, ee)
+	if err != nil {
+		return nil, opError("pmap", f, err)
+	}
+	if err := ee.err("pmap", f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (o *operations) pmapSlice(slice any, f any, ee *elementErrors) (any, error) {
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func {
+		return nil, ErrExpected2ndArgumentToBeFunction
+	}
+	var elemType reflect.Type
+	if l > 0 {
+		elemType = av.Type().Elem()
+	}
+	p := planFor(fv.Type(), elemType)
+	if len(p.in) > 1 {
+		return nil, ErrInputFuncMustTake0or1Arguments
+	}
+	if p.numOut != 1 && p.numOut != 2 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
+	}
+	if p.outErr != nil {
+		return nil, p.outErr
+	}
+
+	results := make([]reflect.Value, l)
+	errs := make([]error, l)
+	err = o.parallel(l, ee.policy == ErrorPolicyFailFast, func(o *operations, i int) error {
+		ev := av.Index(i)
+		var args []reflect.Value
+		if len(p.in) == 1 {
+			arg := ev
+			if !p.elemAssignable {
+				var err error
+				if arg, err = o.assign(i, ev, p.in[0]); err != nil {
+					errs[i] = err
+					return err
+				}
+			}
+			args = []reflect.Value{arg}
+		}
+		r, err := o.call(fv, p, i, ev, args)
+		if err == nil && p.hasErr && !r[1].IsNil() {
+			err = callbackError(i, ev, r[1].Interface().(error))
+		}
+		if err != nil {
+			errs[i] = err
+			return err
+		}
+		results[i] = r[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The results are gathered in order, so errors are handled as map would
+	ra := make([]reflect.Value, 0, l)
+	var newType reflect.Type
+	for i := 0; i < l; i++ {
+		v := results[i]
+		if errs[i] != nil {
+			sv, err := ee.handle(i, errs[i], p.out)
+			if err != nil {
+				return nil, err
+			}
+			v = sv
+		}
+		if !v.IsValid() {
+			continue
+		}
+		newType = widenType(newType, v.Type())
+		ra = append(ra, v)
+	}
+	if !p.hasErr {
+		newType = p.out
+	}
+	return sliceOfValues(newType, ra).Interface(), nil
+}
+
+// pfilter is filter calling f on several goroutines. The result is in the order of slice.
+func (o *operations) pfilter(slice any, f any) (any, error) {
+	ee := o.elementErrors()
+	r, err := o.pfilterSlice(slice, f, ee)
+	if err != nil {
+		return nil, opError("pfilter", f, err)
+	}
+	if err := ee.err("pfilter", f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (o *operations) pfilterSlice(slice any, f any, ee *elementErrors) (any, error) {
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func {
+		return nil, ErrExpected2ndArgumentToBeFunction
+	}
+	var sliceElemType reflect.Type
+	if av.Kind() == reflect.Slice {
+		sliceElemType = av.Type().Elem()
+	}
+	p := planFor(fv.Type(), sliceElemType)
+	if len(p.in) > 1 {
+		return nil, ErrInputFuncMustTake0or1Arguments
+	}
+	if p.numOut != 1 && p.numOut != 2 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
+	}
+	if p.outErr != nil {
+		return nil, p.outErr
+	}
+	if !p.returnsBool {
+		return nil, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, p.out)
+	}
+	// The result has the type of f's parameter, as with filter
+	elemType := sliceElemType
+	if len(p.in) == 1 {
+		elemType = p.in[0]
+		if sliceElemType != nil && !p.elemInterface && !p.elemAssignable && o.coercion != CoercionNumeric {
+			return nil, &OpError{Index: -1, Cause: fmt.Errorf("elements %w: %s", ErrItemNotAssignable, elemType)}
+		}
+	} else if elemType == nil {
+		elemType = anyType
+	}
+
+	keep := make([]reflect.Value, l)
+	passed := make([]bool, l)
+	errs := make([]error, l)
+	err = o.parallel(l, ee.policy == ErrorPolicyFailFast, func(o *operations, i int) error {
+		ev := av.Index(i)
+		keep[i] = ev
+		var args []reflect.Value
+		if len(p.in) == 1 {
+			arg, err := o.argument(i, ev, elemType)
+			if err != nil {
+				errs[i] = err
+				return err
+			}
+			keep[i] = arg
+			args = []reflect.Value{arg}
+		}
+		r, err := o.call(fv, p, i, ev, args)
+		if err == nil && p.hasErr && !r[1].IsNil() {
+			err = callbackError(i, ev, r[1].Interface().(error))
+		}
+		if err != nil {
+			errs[i] = err
+			return err
+		}
+		passed[i] = r[0].Bool()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nra := reflect.MakeSlice(reflect.SliceOf(elemType), 0, l)
+	for i := 0; i < l; i++ {
+		if errs[i] != nil {
+			sv, err := ee.handle(i, errs[i], boolType)
+			if err != nil {
+				return nil, err
+			}
+			// Elements which could not be passed to f cannot be kept in the result either
+			passed[i] = sv.IsValid() && sv.Bool() && keep[i].Type().AssignableTo(elemType)
+		}
+		if passed[i] {
+			nra = reflect.Append(nra, keep[i])
+		}
+	}
+	return nra.Interface(), nil
+}
+
+// parallel calls apply for 0 to l-1 on the configured number of goroutines, taking the indexes in order. apply is
+// given a copy of o whose context is cancelled when an earlier index fails, so callbacks taking a context.Context stop
+// outstanding work. When failFast is true no more are started after apply returns an error, so every index before
+// the first failing one has been applied. Once the context is done no more are started either, and its error is
+// returned. A *PanicError from apply, with WithRepanic, is panicked again on the calling goroutine.
+func (o *operations) parallel(l int, failFast bool, apply func(o *operations, i int) error) error {
+	workers := o.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, l)
+	var next atomic.Int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	var stopped error
+	var panicked *PanicError
+	var once, panicOnce sync.Once
+
+	// The cancel functions of the indexes being applied, so those after the first failing one can be cancelled
+	var mu sync.Mutex
+	cancels := map[int]context.CancelFunc{}
+	failed := l
+	fail := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = min(failed, i)
+		for j, cancel := range cancels {
+			if j > failed {
+				cancel()
+			}
+		}
+	}
+	run := func(i int) error {
+		ctx, cancel := context.WithCancel(o.context())
+		defer cancel()
+		mu.Lock()
+		if i > failed {
+			// Started as an earlier index failed, and not needed
+			mu.Unlock()
+			return nil
+		}
+		cancels[i] = cancel
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(cancels, i)
+			mu.Unlock()
+		}()
+		defer func() {
+			if v := recover(); v != nil {
+				pe, ok := v.(*PanicError)
+				if !ok {
+					panic(v)
+				}
+				panicOnce.Do(func() { panicked = pe })
+				stop.Store(true)
+				fail(i)
+			}
+		}()
+		eo := *o
+		eo.ctx = ctx
+		return apply(&eo, i)
+	}
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				i := int(next.Add(1) - 1)
+				if i >= l {
+					return
+				}
+				if err := o.checkContext(i); err != nil {
+					once.Do(func() { stopped = err })
+					stop.Store(true)
+					return
+				}
+				if err := run(i); err != nil && failFast {
+					stop.Store(true)
+					fail(i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if panicked != nil {
+		panic(panicked)
+	}
+	return stopped
+}
@@ -0,0 +1,205 @@
+package funtemplates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestParallelTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "pmap keeps the order",
+			opts:       []Option{WithWorkers(4)},
+			template:   "{{ pmap $.Data $.Slow }}",
+			want:       "[#1 #2 #3 #4 #5 #6 #7 #8]",
+			correctErr: NoError,
+		},
+		{
+			name:       "pmap infers the result type like map",
+			template:   "{{ printf \"%T\" (pmap $.Data $.Any) }} {{ printf \"%T\" (pmap $.Data $.Double) }}",
+			want:       "[]interface {} []int",
+			correctErr: NoError,
+		},
+		{
+			name:       "pmap with one worker",
+			opts:       []Option{WithWorkers(1)},
+			template:   "{{ pmap $.Data $.Slow }}",
+			want:       "[#1 #2 #3 #4 #5 #6 #7 #8]",
+			correctErr: NoError,
+		},
+		{
+			name:       "pmap of nothing",
+			template:   "{{ pmap nil $.Slow }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "pmap error",
+			template:   "{{ pmap $.Data $.Fail }}",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:       "pmap skipping errors",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ pmap $.Data $.Fail }}",
+			want:       "[1 2 4 5 7 8]",
+			correctErr: NoError,
+		},
+		{
+			name:       "pmap not a function",
+			template:   "{{ pmap $.Data 1 }}",
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+		{
+			name:       "pfilter keeps the order",
+			opts:       []Option{WithWorkers(3)},
+			template:   "{{ pfilter $.Data $.Even }}",
+			want:       "[2 4 6 8]",
+			correctErr: NoError,
+		},
+		{
+			name:       "pfilter of interfaces",
+			template:   "{{ pfilter $.Mixed $.Even }}",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "pfilter substituting errors",
+			opts:       []Option{WithSubstitute(true)},
+			template:   "{{ pfilter $.Mixed $.Even }}",
+			want:       "[2 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "pfilter must return bool",
+			template:   "{{ pfilter $.Data $.Slow }}",
+			correctErr: ErrorIs(ErrExpectedFirstReturnToBeBool),
+		},
+	}
+	data := map[string]any{
+		"Data":  []int{1, 2, 3, 4, 5, 6, 7, 8},
+		"Mixed": []any{1, 2, "three", 4},
+		"Slow": func(i int) string {
+			time.Sleep(time.Duration(8-i) * time.Millisecond)
+			return "#" + strconv.Itoa(i)
+		},
+		"Any":    func(i int) any { return i },
+		"Double": func(i int) (int, error) { return i * 2, nil },
+		"Fail": func(i int) (int, error) {
+			if i%3 == 0 {
+				return 0, errTest
+			}
+			return i, nil
+		},
+		"Even": func(i int) bool { return i%2 == 0 },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(tt.opts...)).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("parallel got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("parallel diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestPMapStopsOnFirstError(t *testing.T) {
+	var calls atomic.Int32
+	data := make([]int, 1000)
+	pmap := TextFunctions(WithWorkers(2))["pmap"].(func(any, any) (any, error))
+	_, err := pmap(data, func(i int) (int, error) {
+		calls.Add(1)
+		return 0, errTest
+	})
+	var oe *OpError
+	if !errors.As(err, &oe) || oe.Op != "pmap" || oe.Index != 0 {
+		t.Errorf("expected the error of item 0, got %v", err)
+	}
+	if n := calls.Load(); n == int32(len(data)) {
+		t.Errorf("expected no more elements to be started after the first error, got %d calls", n)
+	}
+}
+
+func TestPMapCancelsOutstandingWork(t *testing.T) {
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	pmap := TextFunctions(WithWorkers(2))["pmap"].(func(any, any) (any, error))
+	_, err := pmap([]int{0, 1}, func(ctx context.Context, i int) (int, error) {
+		if i == 0 {
+			<-started
+			return 0, errTest
+		}
+		close(started)
+		select {
+		case <-ctx.Done():
+			cancelled.Store(true)
+			return 0, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return i, nil
+		}
+	})
+	var oe *OpError
+	if !errors.As(err, &oe) || oe.Index != 0 || !errors.Is(err, errTest) {
+		t.Errorf("expected the error of item 0, got %v", err)
+	}
+	if !cancelled.Load() {
+		t.Errorf("expected the context of item 1 to be cancelled")
+	}
+}
+
+func TestPFilterRepanicsOnTheCallingGoroutine(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(*PanicError); !ok {
+			t.Errorf("expected a panic with a *PanicError")
+		}
+	}()
+	pfilter := TextFunctions(WithWorkers(4), WithRepanic(true))["pfilter"].(func(any, any) (any, error))
+	_, _ = pfilter([]int{1, 2, 3, 4}, func(i int) bool { panic("boom") })
+}
+
+func BenchmarkPMapTemplateFunc(b *testing.B) {
+	data := make([]int, 64)
+	work := func(i int) int {
+		time.Sleep(10 * time.Microsecond)
+		return i
+	}
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := MapTemplateFunc(data, work); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pmap", func(b *testing.B) {
+		pmap := TextFunctions(WithWorkers(8))["pmap"].(func(any, any) (any, error))
+		for i := 0; i < b.N; i++ {
+			if _, err := pmap(data, work); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
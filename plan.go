@@ -0,0 +1,72 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// plan is the analysis of a callback's signature against the element type of the slice it is applied to. Plans are
+// cached, so operations called repeatedly with the same types, such as inside a range, only analyse them once.
+type plan struct {
+	// ctx is true when the first parameter is a context.Context, which is not included in in
+	ctx    bool
+	in     []reflect.Type
+	out    reflect.Type // the first return, nil if there is none
+	numOut int
+	hasErr bool
+	// outErr is the error to report when there is a second return which is not an error
+	outErr      error
+	returnsBool bool
+	// elemAssignable is true when every element can be passed as the first parameter as it is
+	elemAssignable bool
+	// elemInterface is true when the elements are interfaces, so their dynamic values have to be checked
+	elemInterface bool
+}
+
+type planKey struct {
+	f    reflect.Type
+	elem reflect.Type
+}
+
+// plans holds a *plan per planKey. It is shared by every operation and safe for concurrent use.
+var plans sync.Map
+
+// planFor returns the plan for a callback of type ft applied to elements of type elem, which is nil when there is no
+// slice or the elements are not known in advance.
+func planFor(ft, elem reflect.Type) *plan {
+	k := planKey{f: ft, elem: elem}
+	if p, ok := plans.Load(k); ok {
+		return p.(*plan)
+	}
+	p, _ := plans.LoadOrStore(k, newPlan(ft, elem))
+	return p.(*plan)
+}
+
+func newPlan(ft, elem reflect.Type) *plan {
+	p := &plan{numOut: ft.NumOut()}
+	first := 0
+	if ft.NumIn() > 0 && ft.In(0) == contextType {
+		p.ctx = true
+		first = 1
+	}
+	for i := first; i < ft.NumIn(); i++ {
+		p.in = append(p.in, ft.In(i))
+	}
+	if p.numOut > 0 {
+		p.out = ft.Out(0)
+		p.returnsBool = p.out.AssignableTo(boolType)
+	}
+	if p.numOut == 2 {
+		if fvsrt := ft.Out(1); fvsrt.AssignableTo(errorType) || fvsrt.Implements(errorType) {
+			p.hasErr = true
+		} else {
+			p.outErr = fmt.Errorf("%w instead got: %s", ErrExpectedSecondReturnToBeError, fvsrt)
+		}
+	}
+	if elem != nil {
+		p.elemInterface = elem.Kind() == reflect.Interface
+		p.elemAssignable = len(p.in) == 0 || elem.AssignableTo(p.in[0])
+	}
+	return p
+}
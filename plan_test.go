@@ -0,0 +1,39 @@
+package funtemplates
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPlanFor(t *testing.T) {
+	ft := reflect.TypeOf(func(i int) (bool, error) { return false, nil })
+	intType := reflect.TypeOf(0)
+
+	var wg sync.WaitGroup
+	got := make([]*plan, 8)
+	for n := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[n] = planFor(ft, intType)
+		}()
+	}
+	wg.Wait()
+	for _, p := range got[1:] {
+		if p != got[0] {
+			t.Fatal("expected concurrent lookups to share one plan")
+		}
+	}
+
+	p := got[0]
+	if len(p.in) != 1 || p.out != boolType || !p.hasErr || p.outErr != nil || !p.returnsBool || !p.elemAssignable || p.elemInterface {
+		t.Errorf("unexpected plan %+v", p)
+	}
+	if p := planFor(ft, anyType); p.elemAssignable || !p.elemInterface {
+		t.Errorf("expected []any elements to need checking, got %+v", p)
+	}
+	if p := planFor(reflect.TypeOf(func() (int, int) { return 0, 0 }), nil); p.outErr == nil {
+		t.Errorf("expected a second return error, got %+v", p)
+	}
+}
@@ -0,0 +1,161 @@
+package funtemplates
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type ErrorPolicy string
+
+const (
+	// ErrorPolicyFailFast stops at the first failing element and returns its error.
+	ErrorPolicyFailFast ErrorPolicy = "fail"
+	// ErrorPolicySkip leaves failing elements out of the result.
+	ErrorPolicySkip ErrorPolicy = "skip"
+	// ErrorPolicySubstitute uses a substitute value as the result of failing elements, the zero value by default.
+	ErrorPolicySubstitute ErrorPolicy = "substitute"
+	// ErrorPolicyCollect processes every element then returns the errors of all failing elements joined together.
+	ErrorPolicyCollect ErrorPolicy = "collect"
+)
+
+// TryResult is returned by the try variants of the operations. Value is the result with failing elements skipped or
+// substituted, Err joins the errors of the failing elements and Failed lists their indexes.
+type TryResult struct {
+	Value  any
+	Err    error
+	Failed []int
+}
+
+// elementErrors applies an ErrorPolicy to the elements which fail during one operation.
+type elementErrors struct {
+	policy     ErrorPolicy
+	substitute any
+	errs       []error
+	failed     []int
+}
+
+func (o *operations) elementErrors() *elementErrors {
+	return &elementErrors{policy: o.errorPolicy, substitute: o.substitute}
+}
+
+func newElementErrors(policy ErrorPolicy, substitute any) (*elementErrors, error) {
+	switch policy {
+	case ErrorPolicyFailFast, ErrorPolicySkip, ErrorPolicySubstitute, ErrorPolicyCollect:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPolicy, policy)
+	}
+	return &elementErrors{policy: policy, substitute: substitute}, nil
+}
+
+// handle records the failure of element i. It returns a non-nil error when the operation should stop, otherwise the
+// value of type t to use as the element's result, which is invalid when the element should be skipped.
+func (e *elementErrors) handle(i int, err error, t reflect.Type) (reflect.Value, error) {
+	if errors.Is(err, ErrBudgetExceeded) || errors.Is(err, ErrNotForExecution) {
+		return reflect.Value{}, err
+	}
+	e.errs = append(e.errs, err)
+	e.failed = append(e.failed, i)
+	switch e.policy {
+	case ErrorPolicySubstitute:
+		if e.substitute == nil {
+			return reflect.Zero(t), nil
+		}
+		sv := reflect.ValueOf(e.substitute)
+		if !sv.Type().AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("%w: %s to %s", ErrSubstituteNotAssignable, sv.Type(), t)
+		}
+		return sv, nil
+	case ErrorPolicySkip, ErrorPolicyCollect:
+		return reflect.Value{}, nil
+	}
+	return reflect.Value{}, err
+}
+
+// name sets the operation and callback type of the recorded errors.
+func (e *elementErrors) name(op string, f any) {
+	for i, err := range e.errs {
+		e.errs[i] = opError(op, f, err)
+	}
+}
+
+// err returns the joined errors when the policy is ErrorPolicyCollect.
+func (e *elementErrors) err(op string, f any) error {
+	if e.policy != ErrorPolicyCollect {
+		return nil
+	}
+	e.name(op, f)
+	return errors.Join(e.errs...)
+}
+
+// stoppedBy reports whether err is the error of the last failing element, as returned when failing fast.
+func (e *elementErrors) stoppedBy(err error) bool {
+	return len(e.errs) > 0 && e.errs[len(e.errs)-1] == err
+}
+
+// result builds the TryResult of an operation. err is the error which stopped it, if any, when failing fast.
+func (e *elementErrors) result(op string, f any, v any, err error) TryResult {
+	e.name(op, f)
+	if err != nil {
+		return TryResult{Err: e.errs[len(e.errs)-1], Failed: e.failed}
+	}
+	return TryResult{Value: v, Err: errors.Join(e.errs...), Failed: e.failed}
+}
+
+// tryArgs reads the optional policy and substitute of the try variants. The policy defaults to ErrorPolicyCollect.
+func tryArgs(args []any) (*elementErrors, error) {
+	policy := ErrorPolicyCollect
+	var substitute any
+	switch len(args) {
+	case 2:
+		substitute = args[1]
+		fallthrough
+	case 1:
+		switch p := args[0].(type) {
+		case ErrorPolicy:
+			policy = p
+		case string:
+			policy = ErrorPolicy(p)
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnknownPolicy, args[0])
+		}
+	case 0:
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedPolicyAndSubstitute, len(args))
+	}
+	return newElementErrors(policy, substitute)
+}
+
+func MapTryTemplateFunc(slice any, f any, args ...any) (TryResult, error) {
+	return defaultOperations.mapTry(slice, f, args...)
+}
+
+func FilterTryTemplateFunc(slice any, f any, args ...any) (TryResult, error) {
+	return defaultOperations.filterTry(slice, f, args...)
+}
+
+// mapTry is map returning a TryResult. Only errors which are not about a single element, such as the wrong kind of
+// function, are returned as an error.
+func (o *operations) mapTry(slice any, f any, args ...any) (TryResult, error) {
+	ee, err := tryArgs(args)
+	if err != nil {
+		return TryResult{}, opError("mapTry", f, err)
+	}
+	r, err := o.mapSlice(slice, f, ee)
+	if err != nil && !ee.stoppedBy(err) {
+		return TryResult{}, opError("mapTry", f, err)
+	}
+	return ee.result("mapTry", f, r, err), nil
+}
+
+func (o *operations) filterTry(slice any, f any, args ...any) (TryResult, error) {
+	ee, err := tryArgs(args)
+	if err != nil {
+		return TryResult{}, opError("filterTry", f, err)
+	}
+	r, err := o.filterSlice(slice, f, ee)
+	if err != nil && !ee.stoppedBy(err) {
+		return TryResult{}, opError("filterTry", f, err)
+	}
+	return ee.result("filterTry", f, r, err), nil
+}
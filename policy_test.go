@@ -0,0 +1,205 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"strconv"
+	"testing"
+	"text/template"
+)
+
+func TestErrorPolicies(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Fail fast by default",
+			template:   "{{ map $.Data $.Format }}",
+			correctErr: ErrorIs(ErrCallbackReturnedError),
+		},
+		{
+			name:       "Skip failing elements of map",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ map $.Data $.Format }}",
+			want:       "[#1 #3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Substitute the zero value",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySubstitute)},
+			template:   "{{ map $.Data $.Format | len }}",
+			want:       "4",
+			correctErr: NoError,
+		},
+		{
+			name:       "Substitute a value",
+			opts:       []Option{WithSubstitute("n/a")},
+			template:   "{{ map $.Data $.Format }}",
+			want:       "[#1 n/a #3 n/a]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Substitute must be assignable",
+			opts:       []Option{WithSubstitute(0)},
+			template:   "{{ map $.Data $.Format }}",
+			correctErr: ErrorIs(ErrSubstituteNotAssignable),
+		},
+		{
+			name:       "Collect all errors",
+			opts:       []Option{WithErrorPolicy(ErrorPolicyCollect)},
+			template:   "{{ map $.Data $.Format }}",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:       "Skip failing elements of filter",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ filter $.Data $.Small }}",
+			want:       "[1 2]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Substitute true keeps failing elements of filter",
+			opts:       []Option{WithSubstitute(true)},
+			template:   "{{ filter $.Data $.Small }}",
+			want:       "[1 2 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Unassignable elements are skipped by filter",
+			opts:       []Option{WithSubstitute(true)},
+			template:   "{{ filter $.Mixed $.Small }}",
+			want:       "[1 2 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry collects by default",
+			template:   "{{ $r := mapTry $.Data $.Format }}{{ $r.Value }} {{ $r.Failed }}",
+			want:       "[#1 #3] [1 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry with a substitute",
+			template:   `{{ $r := mapTry $.Data $.Format "substitute" "-" }}{{ $r.Value }} {{ $r.Failed }}`,
+			want:       "[#1 - #3 -] [1 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry failing fast",
+			template:   `{{ $r := mapTry $.Data $.Format "fail" }}{{ $r.Value }} {{ $r.Failed }} {{ $r.Err }}`,
+			want:       "<no value> [1] mapTry: item 1: f returned an error: test error",
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry without failures",
+			template:   `{{ $r := mapTry $.Data $.Quote }}{{ $r.Value }} {{ len $r.Failed }} {{ $r.Err }}`,
+			want:       `["1" "2" "3" "4"] 0 <nil>`,
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry ignores the FuncMap policy",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   `{{ $r := mapTry $.Data $.Format }}{{ $r.Failed }}`,
+			want:       "[1 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "mapTry still fails on a bad function",
+			template:   `{{ mapTry $.Data $.Data }}`,
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+		{
+			name:       "mapTry unknown policy",
+			template:   `{{ mapTry $.Data $.Format "retry" }}`,
+			correctErr: ErrorIs(ErrUnknownPolicy),
+		},
+		{
+			name:       "mapTry too many arguments",
+			template:   `{{ mapTry $.Data $.Format "skip" 1 2 }}`,
+			correctErr: ErrorIs(ErrExpectedPolicyAndSubstitute),
+		},
+		{
+			name:       "filterTry",
+			template:   `{{ $r := filterTry $.Data $.Small "skip" }}{{ $r.Value }} {{ $r.Failed }}`,
+			want:       "[1 2] [3]",
+			correctErr: NoError,
+		},
+	}
+	data := map[string]any{
+		"Data":  []int{1, 2, 3, 4},
+		"Mixed": []any{1, 2, "three", 4},
+		"Format": func(i int) (string, error) {
+			if i%2 == 0 {
+				return "", errTest
+			}
+			return "#" + strconv.Itoa(i), nil
+		},
+		"Quote": func(i int) string {
+			return strconv.Quote(strconv.Itoa(i))
+		},
+		"Small": func(i int) (bool, error) {
+			if i > 3 {
+				return false, errTest
+			}
+			return i < 3, nil
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(tt.opts...)).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("ErrorPolicy got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("ErrorPolicy diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorPolicyCollectJoinsErrors(t *testing.T) {
+	format := func(i int) (string, error) {
+		if i%2 == 0 {
+			return "", errTest
+		}
+		return strconv.Itoa(i), nil
+	}
+	funcs := TextFunctions(WithErrorPolicy(ErrorPolicyCollect))
+	mapFunc := funcs["map"].(func(any, any) (any, error))
+	_, err := mapFunc([]int{1, 2, 3, 4}, format)
+	want := "map: item 1: f returned an error: test error\nmap: item 3: f returned an error: test error"
+	if err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %q", err, want)
+	}
+	var oe *OpError
+	if !errors.As(err, &oe) || oe.Index != 1 {
+		t.Errorf("expected the first OpError to be for item 1, got %v", oe)
+	}
+
+	r, err := MapTryTemplateFunc([]int{1, 2, 3, 4}, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{1, 3}, r.Failed); diff != "" {
+		t.Errorf("Failed diff =\n %s", diff)
+	}
+	if diff := cmp.Diff([]string{"1", "3"}, r.Value); diff != "" {
+		t.Errorf("Value diff =\n %s", diff)
+	}
+	if !errors.Is(r.Err, errTest) {
+		t.Errorf("expected Err to wrap the callback error, got %v", r.Err)
+	}
+}
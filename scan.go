@@ -0,0 +1,110 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ScanTemplateFunc folds f over slice like a reduce, returning every intermediate accumulator. It takes either
+// (f), using the first element as the initial accumulator, or (seed, f). f must be of the form func(A, T) A or
+// func(A, T) (A, error).
+func ScanTemplateFunc(slice any, args ...any) (any, error) {
+	return defaultOperations.scan(slice, args...)
+}
+
+func (o *operations) scan(slice any, args ...any) (any, error) {
+	r, err := o.scanValues(slice, args)
+	if err != nil {
+		var f any
+		if len(args) > 0 {
+			f = args[len(args)-1]
+		}
+		return nil, opError("scan", f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) scanValues(slice any, args []any) (any, error) {
+	var seed reflect.Value
+	var f any
+	switch len(args) {
+	case 1:
+		f = args[0]
+	case 2:
+		seed, f = reflect.ValueOf(args[0]), args[1]
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedSeedAndFunction, len(args))
+	}
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	c, err := o.newCallback(f, 2)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.in) != 2 {
+		return nil, fmt.Errorf("%w got: %d", ErrInputFuncMustTake2Arguments, len(c.in))
+	}
+	accType := c.in[0]
+	if c.out.Kind() != reflect.Interface && !c.out.AssignableTo(accType) {
+		return nil, fmt.Errorf("%w: %s is not assignable to %s", ErrExpectedAccumulatorReturn, c.out, accType)
+	}
+
+	results := make([]reflect.Value, 0, l)
+	start := 0
+	var acc reflect.Value
+	switch {
+	case len(args) == 2:
+		if acc, err = seedFor(seed, accType); err != nil {
+			return nil, err
+		}
+	case l > 0:
+		acc = av.Index(0)
+		results = append(results, acc)
+		start = 1
+	}
+	for i := start; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		if acc, err = c.call(i, acc, av.Index(i)); err != nil {
+			return nil, err
+		}
+		results = append(results, acc)
+	}
+
+	resultType := c.out
+	if c.hasErr {
+		resultType = inferType(results)
+	}
+	nra := reflect.MakeSlice(reflect.SliceOf(resultType), len(results), len(results))
+	for i, r := range results {
+		if r.Kind() == reflect.Interface && resultType.Kind() != reflect.Interface {
+			r = r.Elem()
+		}
+		if !r.Type().AssignableTo(resultType) {
+			return nil, notAssignableError(i, av.Index(i), resultType)
+		}
+		nra.Index(i).Set(r)
+	}
+	return nra.Interface(), nil
+}
+
+// seedFor converts seed to the accumulator type, allowing numeric conversions so literal seeds such as 0 work with
+// float accumulators. Only numbers the accumulator can hold are converted, so 1.7 is not truncated to an int nor -1
+// wrapped to a uint.
+func seedFor(seed reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !seed.IsValid() {
+		return argumentFor(-1, seed, t)
+	}
+	if seed.Type().AssignableTo(t) {
+		return seed, nil
+	}
+	if n, err := numberOf(seed); err == nil {
+		if v, ok := n.convert(t); ok {
+			return v, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("seed %v %w: %s", seed, ErrItemNotAssignable, t)
+}
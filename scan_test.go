@@ -0,0 +1,150 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestScanTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Running total without seed",
+			template:   "{{ scan $.DataInts $.Funcs.add }}",
+			want:       "[1 3 6 10]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Running total with seed",
+			template:   "{{ scan $.DataInts 100 $.Funcs.add }}",
+			want:       "[101 103 106 110]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Result typed from the function",
+			template:   "{{ printf \"%T\" (scan $.DataInts 0 $.Funcs.add) }}",
+			want:       "[]int",
+			correctErr: NoError,
+		},
+		{
+			name:       "Seed converted to accumulator type",
+			template:   "{{ scan $.Transactions 10 $.Funcs.balance }}",
+			want:       "[12.5 7.5 8]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Fractional seed for an integer accumulator",
+			template:   "{{ scan $.DataInts 1.7 $.Funcs.add }}",
+			want:       "",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "Negative seed for an unsigned accumulator",
+			template:   "{{ scan $.DataUints -1 $.Funcs.addU }}",
+			want:       "",
+			correctErr: ErrorIs(ErrItemNotAssignable),
+		},
+		{
+			name:       "Whole float seed for an integer accumulator",
+			template:   "{{ scan $.DataInts 2.0 $.Funcs.add }}",
+			want:       "[3 5 8 12]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Accumulator of a different type",
+			template:   `{{ scan $.DataInts "" $.Funcs.concat }}`,
+			want:       "[1 12 123 1234]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Error returning function",
+			template:   "{{ printf \"%T %v\" (scan $.DataInts 0 $.Funcs.addE) (scan $.DataInts 0 $.Funcs.addE) }}",
+			want:       "[]int [1 3 6 10]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Function error",
+			template:   "{{ scan $.DataInts 0 $.Funcs.fail }}",
+			want:       "",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:       "Empty slice",
+			template:   "{{ scan nil $.Funcs.add }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Function must take two parameters",
+			template:   "{{ scan $.DataInts 0 $.Funcs.one }}",
+			want:       "",
+			correctErr: ErrorIs(ErrInputFuncMustTake2Arguments),
+		},
+		{
+			name:       "Function must return the accumulator",
+			template:   "{{ scan $.DataInts 0 $.Funcs.wrong }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedAccumulatorReturn),
+		},
+		{
+			name:       "Missing function",
+			template:   "{{ scan $.DataInts }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedSeedAndFunction),
+		},
+		{
+			name:       "Correct error on not a func",
+			template:   "{{ scan $.DataInts 0 1 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		DataInts     []int
+		DataUints    []uint
+		Transactions []float64
+		Funcs        map[string]any
+	}{
+		DataInts:     []int{1, 2, 3, 4},
+		DataUints:    []uint{1, 2},
+		Transactions: []float64{2.5, -5, 0.5},
+		Funcs: map[string]any{
+			"add":     func(a, b int) int { return a + b },
+			"addE":    func(a, b int) (int, error) { return a + b, nil },
+			"addU":    func(a, b uint) uint { return a + b },
+			"balance": func(a, b float64) float64 { return a + b },
+			"concat":  func(a string, b int) string { return a + string(rune('0'+b)) },
+			"fail":    func(a, b int) (int, error) { return 0, errTest },
+			"one":     func(a int) int { return a },
+			"wrong":   func(a, b int) string { return "" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("ScanTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("ScanTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package funtemplates
+
+import (
+	"reflect"
+)
+
+type RecordField struct {
+	Name  string
+	Value any
+}
+
+// Record is an ordered set of named values produced by SelectTemplateFunc. Ranging over its Fields visits them in the
+// order they were selected, and ByName holds them by path, so {{ .ByName.Email }} or {{ index .ByName "Team.Name" }}
+// reads one.
+type Record struct {
+	Fields []RecordField
+	ByName map[string]any
+}
+
+// Get returns the value of the named field, or nil if the record has no such field.
+func (r Record) Get(name string) any {
+	return r.ByName[name]
+}
+
+func (r Record) Names() []string {
+	names := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func (r Record) Values() []any {
+	values := make([]any, len(r.Fields))
+	for i, f := range r.Fields {
+		values[i] = f.Value
+	}
+	return values
+}
+
+// SelectTemplateFunc projects each element of slice into a Record of the given field paths, such as "Name" or
+// "Team.Name".
+func SelectTemplateFunc(slice any, paths ...string) ([]Record, error) {
+	return defaultOperations.selectFields(slice, paths...)
+}
+
+func (o *operations) selectFields(slice any, paths ...string) ([]Record, error) {
+	r, err := o.selectRecords(slice, paths)
+	if err != nil {
+		return nil, opError("select", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) selectRecords(slice any, paths []string) ([]Record, error) {
+	if len(paths) == 0 {
+		return nil, ErrExpectedFieldPath
+	}
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, l)
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		r := Record{Fields: make([]RecordField, len(paths)), ByName: make(map[string]any, len(paths))}
+		for n, path := range paths {
+			v, err := resolvePath(av.Index(i), path)
+			if err != nil {
+				return nil, elementError(i, av.Index(i), err)
+			}
+			r.Fields[n] = RecordField{Name: path, Value: indirectInterfaceValue(v)}
+			r.ByName[path] = r.Fields[n].Value
+		}
+		records[i] = r
+	}
+	return records, nil
+}
+
+// PluckTemplateFunc returns the value at path for each element of slice. The result is typed like MapTemplateFunc
+// would type it.
+func PluckTemplateFunc(slice any, path string) (any, error) {
+	return defaultOperations.pluck(slice, path)
+}
+
+func (o *operations) pluck(slice any, path string) (any, error) {
+	r, err := o.pluckValues(slice, path)
+	if err != nil {
+		return nil, opError("pluck", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) pluckValues(slice any, path string) (any, error) {
+	av, l, err := o.sliceArg(slice)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]reflect.Value, l)
+	valid := make([]reflect.Value, 0, l)
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		if values[i], err = resolvePath(av.Index(i), path); err != nil {
+			return nil, elementError(i, av.Index(i), err)
+		}
+		if values[i].IsValid() {
+			valid = append(valid, values[i])
+		}
+	}
+	t := inferType(valid)
+	if len(valid) != l {
+		t = anyType
+	}
+	nra := reflect.MakeSlice(reflect.SliceOf(t), l, l)
+	for i, v := range values {
+		if v.IsValid() {
+			nra.Index(i).Set(v)
+		}
+	}
+	return nra.Interface(), nil
+}
@@ -0,0 +1,147 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+type team struct {
+	Name string
+}
+
+type audit struct {
+	CreatedBy string
+}
+
+type member struct {
+	audit
+	Name  string
+	Email string
+	Team  *team
+	Meta  map[string]any
+	email string
+}
+
+func TestSelectTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Select fields",
+			template:   `{{ range select $.Members "Name" "Email" }}{{ .Get "Name" }} <{{ .Get "Email" }}>;{{ end }}`,
+			want:       "alice <a@example.com>;bob <b@example.com>;",
+			correctErr: NoError,
+		},
+		{
+			name:       "Fields by name",
+			template:   `{{ range select $.Members "Name" "Team.Name" }}{{ .ByName.Name }} {{ index .ByName "Team.Name" }};{{ end }}`,
+			want:       "alice red;bob blue;",
+			correctErr: NoError,
+		},
+		{
+			name:       "Records keep field order",
+			template:   `{{ range select $.Members "Team.Name" "Name" }}{{ range .Fields }}{{ .Name }}={{ .Value }} {{ end }}{{ end }}`,
+			want:       "Team.Name=red Name=alice Team.Name=blue Name=bob ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Embedded struct fields and map keys",
+			template:   `{{ range select $.Members "CreatedBy" "Meta.role" }}{{ .Values }}{{ end }}`,
+			want:       "[root admin][root <nil>]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Maps",
+			template:   `{{ range select $.Rows "id" "name" }}{{ .Names }}{{ .Values }}{{ end }}`,
+			want:       "[id name][1 x]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Unknown field reports the element",
+			template:   `{{ select $.Members "Name" "Phone" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrFieldNotFound),
+		},
+		{
+			name:       "Unexported field",
+			template:   `{{ select $.Members "email" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrFieldNotFound),
+		},
+		{
+			name:       "Nil pointer in path",
+			template:   `{{ select $.NoTeam "Team.Name" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrNilInPath),
+		},
+		{
+			name:       "Needs a field",
+			template:   `{{ select $.Members }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFieldPath),
+		},
+		{
+			name:       "Pluck",
+			template:   `{{ printf "%T %v" (pluck $.Members "Team.Name") (pluck $.Members "Team.Name") }}`,
+			want:       "[]string [red blue]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Pluck missing map keys",
+			template:   `{{ pluck $.Members "Meta.role" }}`,
+			want:       "[admin <nil>]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Pluck pointers",
+			template:   `{{ range pluck $.Members "Team" }}{{ .Name }}{{ end }}`,
+			want:       "redblue",
+			correctErr: NoError,
+		},
+		{
+			name:       "First parameter must be a slice not a string",
+			template:   `{{ pluck "abc" "Name" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		Members []*member
+		NoTeam  []member
+		Rows    []map[string]any
+	}{
+		Members: []*member{
+			{audit: audit{"root"}, Name: "alice", Email: "a@example.com", Team: &team{"red"}, Meta: map[string]any{"role": "admin"}},
+			{audit: audit{"root"}, Name: "bob", Email: "b@example.com", Team: &team{"blue"}},
+		},
+		NoTeam: []member{{Name: "carol"}},
+		Rows:   []map[string]any{{"id": 1, "name": "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("SelectTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("SelectTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package funtemplates
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+)
+
+// SeqTemplateFunc generates the numbers from start up to, but not including, stop. It takes (stop), (start, stop)
+// or (start, stop, step) and counts down when start is greater than stop. A float argument produces []float64,
+// otherwise the result is []int.
+func SeqTemplateFunc(args ...any) (any, error) {
+	return defaultOperations.seq(args...)
+}
+
+// SeqIterTemplateFunc is the lazy form of SeqTemplateFunc returning an iter.Seq[int] or iter.Seq[float64], which
+// can be ranged over or passed to the other operations without building the whole slice up front.
+func SeqIterTemplateFunc(args ...any) (any, error) {
+	return defaultOperations.seqIter(args...)
+}
+
+func (o *operations) seq(args ...any) (any, error) {
+	s, err := newSequence(args)
+	if err != nil {
+		return nil, opError("seq", nil, err)
+	}
+	if s.n > maxEagerSeq {
+		return nil, opError("seq", nil, fmt.Errorf("%w: %d elements exceeds the limit of %d, use seqIter", ErrResultTooLarge, s.n, maxEagerSeq))
+	}
+	if err := o.checkResult(s.n); err != nil {
+		return nil, opError("seq", nil, err)
+	}
+	if s.float {
+		r := make([]float64, 0, s.n)
+		for i := 0; i < s.n; i++ {
+			if err := o.checkContextEvery(i); err != nil {
+				return nil, opError("seq", nil, err)
+			}
+			r = append(r, s.fstart+float64(i)*s.fstep)
+		}
+		return r, nil
+	}
+	r := make([]int, 0, s.n)
+	for i := 0; i < s.n; i++ {
+		if err := o.checkContextEvery(i); err != nil {
+			return nil, opError("seq", nil, err)
+		}
+		r = append(r, s.start+i*s.step)
+	}
+	return r, nil
+}
+
+func (o *operations) seqIter(args ...any) (any, error) {
+	s, err := newSequence(args)
+	if err != nil {
+		return nil, opError("seqIter", nil, err)
+	}
+	if s.float {
+		return iter.Seq[float64](func(yield func(float64) bool) {
+			for i := 0; i < s.n; i++ {
+				if !yield(s.fstart + float64(i)*s.fstep) {
+					return
+				}
+			}
+		}), nil
+	}
+	return iter.Seq[int](func(yield func(int) bool) {
+		for i := 0; i < s.n; i++ {
+			if !yield(s.start + i*s.step) {
+				return
+			}
+		}
+	}), nil
+}
+
+// maxEagerSeq bounds the number of elements seq will generate, as maxTuples does for the combinatorics, and the
+// number the operations will copy into a slice from an iter.Seq or a stream over one. seqIter is not bounded as it
+// never holds more than one, so it can be ranged over or passed to take.
+const maxEagerSeq = 100_000
+
+// errSeqTooLong is returned when more than maxEagerSeq elements would be copied from an iter.Seq.
+var errSeqTooLong = fmt.Errorf("%w: more than %d elements from an iter.Seq, use lazyMap, lazyFilter and take", ErrResultTooLarge, maxEagerSeq)
+
+type sequence struct {
+	float         bool
+	start, step   int
+	fstart, fstep float64
+	n             int
+}
+
+func newSequence(args []any) (*sequence, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpected1To3Arguments, len(args))
+	}
+	nums := make([]number, len(args))
+	s := &sequence{}
+	for i, a := range args {
+		n, err := numberOf(reflect.ValueOf(a))
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		switch n.kind {
+		case numInt, numUint:
+			if n, err = n.as(numInt); err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i+1, err)
+			}
+		case numFloat:
+			s.float = true
+		default:
+			return nil, fmt.Errorf("argument %d: %w: %T", i+1, ErrNotANumber, a)
+		}
+		nums[i] = n
+	}
+	start, stop := number{kind: numInt}, nums[0]
+	if len(nums) > 1 {
+		start, stop = nums[0], nums[1]
+	}
+	step := number{kind: numInt, i: 1}
+	if start.float() > stop.float() {
+		step.i = -1
+	}
+	if len(nums) > 2 {
+		step = nums[2]
+	}
+
+	if s.float {
+		s.fstart, s.fstep = start.float(), step.float()
+		fstop := stop.float()
+		if s.fstep == 0 || math.IsNaN(s.fstep) {
+			return nil, ErrZeroStep
+		}
+		count := math.Ceil((fstop - s.fstart) / s.fstep)
+		switch {
+		case count >= math.MaxInt:
+			s.n = math.MaxInt
+		case count > 0:
+			s.n = int(count)
+		}
+		return s, nil
+	}
+	if step.i == 0 {
+		return nil, ErrZeroStep
+	}
+	s.start, s.step = int(start.i), int(step.i)
+	var distance, stride uint64
+	switch {
+	case step.i > 0 && stop.i > start.i:
+		distance, stride = uint64(stop.i-start.i), uint64(step.i)
+	case step.i < 0 && stop.i < start.i:
+		distance, stride = uint64(start.i-stop.i), uint64(-step.i)
+	default:
+		return s, nil
+	}
+	count := distance / stride
+	if distance%stride != 0 {
+		count++
+	}
+	s.n = int(min(count, math.MaxInt))
+	return s, nil
+}
+
+// seqToSlice collects an iter.Seq, such as one returned by SeqIterTemplateFunc, into a slice so the operations can
+// index it. Any other value is returned unchanged. Either way it fails when there are more elements than the budget
+// allows, and an iter.Seq fails when it has more than maxEagerSeq.
+func (o *operations) seqToSlice(av reflect.Value) (reflect.Value, error) {
+	if av.Kind() == reflect.Slice {
+		return av, o.checkInput(av.Len())
+	}
+	if av.Kind() != reflect.Func || av.IsNil() || !av.Type().CanSeq() || av.Type().NumIn() != 1 {
+		return av, nil
+	}
+	yieldType := av.Type().In(0)
+	if yieldType.NumIn() != 1 {
+		return av, nil
+	}
+	r := reflect.MakeSlice(reflect.SliceOf(yieldType.In(0)), 0, 0)
+	for v := range av.Seq() {
+		if err := o.checkContextEvery(r.Len()); err != nil {
+			return av, err
+		}
+		if err := o.checkInput(r.Len() + 1); err != nil {
+			return av, err
+		}
+		if r.Len() == maxEagerSeq {
+			return av, errSeqTooLong
+		}
+		r = reflect.Append(r, v)
+	}
+	return r, nil
+}
+
+func (o *operations) collectSeq(slice any) (any, error) {
+	av, err := o.seqToSlice(reflect.ValueOf(slice))
+	if err != nil || !av.IsValid() {
+		return slice, err
+	}
+	return av.Interface(), nil
+}
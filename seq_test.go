@@ -0,0 +1,178 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/arran4/go-template-functional-operations/misc"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestSeqTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Stop only",
+			template:   "{{ seq 5 }}",
+			want:       "[0 1 2 3 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Start and stop",
+			template:   "{{ seq 1 6 }}",
+			want:       "[1 2 3 4 5]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Step",
+			template:   "{{ seq 0 10 3 }}",
+			want:       "[0 3 6 9]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Descending by default when start is greater",
+			template:   "{{ seq 5 0 }}",
+			want:       "[5 4 3 2 1]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Descending step",
+			template:   "{{ seq 10 0 -4 }}",
+			want:       "[10 6 2]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Step in the wrong direction is empty",
+			template:   "{{ seq 0 10 -1 }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Floats",
+			template:   "{{ seq 0 1 0.25 }}",
+			want:       "[0 0.25 0.5 0.75]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Zero step",
+			template:   "{{ seq 0 10 0 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrZeroStep),
+		},
+		{
+			name:       "Not a number",
+			template:   `{{ seq "10" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrNotANumber),
+		},
+		{
+			name:       "Too many arguments",
+			template:   "{{ seq 1 2 3 4 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpected1To3Arguments),
+		},
+		{
+			name:       "Composes with map",
+			template:   "{{ map (seq 1 4) $.Funcs.inc }}",
+			want:       "[2 3 4]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Iterator can be ranged over",
+			template:   "{{ range seqIter 3 }}*{{ end }}",
+			want:       "***",
+			correctErr: NoError,
+		},
+		{
+			name:       "Iterator composes with map and filter",
+			template:   "{{ map (seqIter 1 6) $.Funcs.inc }} {{ filter (seqIter 1 6) $.Funcs.odd }} {{ find (seqIter 2 6) $.Funcs.odd }}",
+			want:       "[2 3 4 5 6] [1 3 5] 3",
+			correctErr: NoError,
+		},
+		{
+			name:       "Huge iterator is lazy",
+			template:   "{{ range seqIter 0 9223372036854775807 }}{{ if eq . 3 }}{{ break }}{{ end }}{{ . }}{{ end }}",
+			want:       "012",
+			correctErr: NoError,
+		},
+		{
+			name:       "Largest eager sequence",
+			template:   "{{ len (seq 100000) }} {{ len (seq 1 100001) }} {{ len (seq 0.0 100000.0) }}",
+			want:       "100000 100000 100000",
+			correctErr: NoError,
+		},
+		{
+			name:       "Eager sequence too large",
+			template:   "{{ seq 100001 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Eager float sequence too large",
+			template:   "{{ seq 0 100000.5 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Largest iterator copied into a slice",
+			template:   "{{ len (map (seqIter 100000) $.Funcs.inc) }} {{ len (collect (seqIter 100000)) }}",
+			want:       "100000 100000",
+			correctErr: NoError,
+		},
+		{
+			name:       "Huge iterator copied into a slice",
+			template:   "{{ map (seqIter 1000000000000) $.Funcs.inc }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Huge iterator collected",
+			template:   "{{ collect (seqIter 1000000000000) }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Huge iterator through a stream",
+			template:   "{{ len (take (lazyMap (seqIter 1000000000000) $.Funcs.inc) 3) }} {{ collect (lazyFilter (seqIter 1000000000000) $.Funcs.odd) }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+		{
+			name:       "Huge eager sequence",
+			template:   "{{ seq 0 9223372036854775807 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrResultTooLarge),
+		},
+	}
+	funcs := misc.MergeMaps(TextFunctions(), misc.SimpleTextFunctions())
+	data := struct {
+		Funcs map[string]any
+	}{
+		Funcs: funcs,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("SeqTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("SeqTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
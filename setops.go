@@ -0,0 +1,164 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func UnionTemplateFunc(args ...any) (any, error) {
+	return defaultOperations.union(args...)
+}
+
+func (o *operations) union(args ...any) (any, error) {
+	return o.setOperation("union", args, func(first bool, inputs, total int) bool {
+		return true
+	})
+}
+
+func IntersectTemplateFunc(args ...any) (any, error) {
+	return defaultOperations.intersect(args...)
+}
+
+func (o *operations) intersect(args ...any) (any, error) {
+	return o.setOperation("intersect", args, func(first bool, inputs, total int) bool {
+		return first && inputs == total
+	})
+}
+
+func DifferenceTemplateFunc(args ...any) (any, error) {
+	return defaultOperations.difference(args...)
+}
+
+func (o *operations) difference(args ...any) (any, error) {
+	return o.setOperation("difference", args, func(first bool, inputs, total int) bool {
+		return first && inputs == 1
+	})
+}
+
+func SymmetricDifferenceTemplateFunc(args ...any) (any, error) {
+	return defaultOperations.symmetricDifference(args...)
+}
+
+func (o *operations) symmetricDifference(args ...any) (any, error) {
+	return o.setOperation("symmetricDifference", args, func(first bool, inputs, total int) bool {
+		return inputs == 1
+	})
+}
+
+// setOperation takes two or more slices optionally followed by a key function defining element identity. keep is
+// asked about each distinct key, in first seen order, given whether it appeared in the first slice and in how many
+// of the total slices it appeared.
+func (o *operations) setOperation(op string, args []any, keep func(first bool, inputs, total int) bool) (any, error) {
+	r, err := o.setOf(args, keep)
+	if err != nil {
+		var f any
+		if len(args) > 0 {
+			f = args[len(args)-1]
+		}
+		return nil, opError(op, f, err)
+	}
+	return r, nil
+}
+
+func (o *operations) setOf(args []any, keep func(first bool, inputs, total int) bool) (any, error) {
+	var c *callback
+	if len(args) > 0 {
+		if fv := reflect.ValueOf(args[len(args)-1]); fv.Kind() == reflect.Func {
+			var err error
+			if c, err = o.newCallback(args[len(args)-1], 1); err != nil {
+				return nil, err
+			}
+			args = args[:len(args)-1]
+		}
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedAtLeastTwoSlices, len(args))
+	}
+	avs := make([]reflect.Value, len(args))
+	for i, slice := range args {
+		av, _, err := o.sliceArg(slice)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		avs[i] = av
+	}
+	elemType, err := commonElemType(avs)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := newKeySet()
+	var elements []reflect.Value
+	var inFirst []bool
+	var inputs []int
+	var lastInput []int
+	for j, av := range avs {
+		if !av.IsValid() {
+			continue
+		}
+		for i := 0; i < av.Len(); i++ {
+			if err := o.checkContext(i); err != nil {
+				return nil, fmt.Errorf("argument %d: %w", j+1, err)
+			}
+			ev := av.Index(i)
+			key := ev
+			if c != nil {
+				if key, err = c.call(i, ev); err != nil {
+					if oe, ok := err.(*OpError); ok {
+						oe.Cause = fmt.Errorf("argument %d: %w", j+1, oe.Cause)
+					}
+					return nil, err
+				}
+			}
+			id, isNew := keys.id(key)
+			if isNew {
+				elements = append(elements, ev)
+				inFirst = append(inFirst, j == 0)
+				inputs = append(inputs, 0)
+				lastInput = append(lastInput, -1)
+			}
+			if lastInput[id] != j {
+				lastInput[id] = j
+				inputs[id]++
+			}
+		}
+	}
+
+	nra := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(elements))
+	for id, ev := range elements {
+		if keep(inFirst[id], inputs[id], len(avs)) {
+			nra = reflect.Append(nra, ev)
+		}
+	}
+	if err := o.checkResult(nra.Len()); err != nil {
+		return nil, err
+	}
+	return nra.Interface(), nil
+}
+
+// commonElemType finds the element type of avs which all other element types are assignable to. Nil slices are
+// ignored.
+func commonElemType(avs []reflect.Value) (reflect.Type, error) {
+	var types []reflect.Type
+	for _, av := range avs {
+		if av.IsValid() {
+			types = append(types, av.Type().Elem())
+		}
+	}
+	if len(types) == 0 {
+		return anyType, nil
+	}
+	for _, candidate := range types {
+		ok := true
+		for _, t := range types {
+			if !t.AssignableTo(candidate) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return candidate, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrIncompatibleElementTypes, types)
+}
@@ -0,0 +1,148 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestSetOperationTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Union preserves first seen order",
+			template:   "{{ union $.A $.B }}",
+			want:       "[1 2 3 4 5]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Union of three",
+			template:   "{{ union $.A $.B $.C }}",
+			want:       "[1 2 3 4 5 9]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Intersect",
+			template:   "{{ intersect $.A $.B }}",
+			want:       "[2 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Intersect of three",
+			template:   "{{ intersect $.A $.B $.C }}",
+			want:       "[3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Difference",
+			template:   "{{ difference $.A $.B }}",
+			want:       "[1]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Symmetric difference",
+			template:   "{{ symmetricDifference $.A $.B }}",
+			want:       "[1 4 5]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Key function defines identity",
+			template:   "{{ range difference $.AllUsers $.Subscribed $.Funcs.id }}{{ .Name }} {{ end }}",
+			want:       "bob ",
+			correctErr: NoError,
+		},
+		{
+			name:       "Non comparable elements",
+			template:   "{{ union $.Lists $.OtherLists }}",
+			want:       "[[1 2] [3] [4]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Interface slices combine with typed slices",
+			template:   "{{ printf \"%T %v\" (union $.A $.Any) (union $.A $.Any) }}",
+			want:       "[]interface {} [1 2 3 x]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Nil slices are empty",
+			template:   "{{ union nil $.A }}",
+			want:       "[1 2 3]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Incompatible element types",
+			template:   "{{ union $.A $.Strings }}",
+			want:       "",
+			correctErr: ErrorIs(ErrIncompatibleElementTypes),
+		},
+		{
+			name:       "Needs two slices",
+			template:   "{{ union $.A }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedAtLeastTwoSlices),
+		},
+		{
+			name:       "Arguments must be slices",
+			template:   "{{ union $.A 1 }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		A          []int
+		B          []int
+		C          []int
+		Any        []any
+		Strings    []string
+		Lists      [][]int
+		OtherLists [][]int
+		AllUsers   []user
+		Subscribed []user
+		Funcs      map[string]any
+	}{
+		A:          []int{1, 2, 3, 2},
+		B:          []int{2, 3, 4, 5},
+		C:          []int{9, 3},
+		Any:        []any{"x", 1},
+		Strings:    []string{"a"},
+		Lists:      [][]int{{1, 2}, {3}},
+		OtherLists: [][]int{{3}, {4}},
+		AllUsers:   []user{{1, "alice"}, {2, "bob"}},
+		Subscribed: []user{{1, "Alice"}},
+		Funcs: map[string]any{
+			"id": func(u user) int { return u.ID },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("UnionTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("UnionTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package funtemplates
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// Stream is a lazy pipeline built by lazyMap and lazyFilter. Nothing is evaluated until it is materialised with
+// collect or take, or ranged over from Go with StreamValues, and then each element passes through all the stages
+// before the next one is started, only as far as is needed. A Stream may be evaluated any number of times,
+// concurrently. It has no methods, so templates cannot range over it without its errors reaching Execute.
+type Stream struct {
+	o      *operations
+	source reflect.Value
+	stages []stage
+	// elem is the type of the values leaving the last stage, nil when it can only be known from the values
+	elem reflect.Type
+}
+
+type stage struct {
+	op     string
+	fv     reflect.Value
+	p      *plan
+	filter bool
+}
+
+func LazyMapTemplateFunc(source any, f any) (*Stream, error) {
+	return defaultOperations.lazyMap(source, f)
+}
+
+func LazyFilterTemplateFunc(source any, f any) (*Stream, error) {
+	return defaultOperations.lazyFilter(source, f)
+}
+
+func TakeTemplateFunc(source any, n int) (any, error) {
+	return defaultOperations.take(source, n)
+}
+
+func CollectTemplateFunc(source any) (any, error) {
+	return defaultOperations.collect(source)
+}
+
+// StreamValues returns the values of s for ranging over from Go. When a stage fails the error is yielded, with a nil
+// value, as the last pair.
+func StreamValues(s *Stream) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		stopped := false
+		err := s.each(func(v reflect.Value) bool {
+			stopped = !yield(indirectInterfaceValue(v), nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+func (o *operations) lazyMap(source any, f any) (*Stream, error) {
+	s, err := o.addStage(source, f, false)
+	if err != nil {
+		return nil, opError("lazyMap", f, err)
+	}
+	return s, nil
+}
+
+func (o *operations) lazyFilter(source any, f any) (*Stream, error) {
+	s, err := o.addStage(source, f, true)
+	if err != nil {
+		return nil, opError("lazyFilter", f, err)
+	}
+	return s, nil
+}
+
+// addStage returns a new stream applying f to the values of source, which is a slice, an iter.Seq or a *Stream.
+func (o *operations) addStage(source any, f any, filter bool) (*Stream, error) {
+	s, err := o.streamOf(source)
+	if err != nil {
+		return nil, err
+	}
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func {
+		return nil, ErrExpected2ndArgumentToBeFunction
+	}
+	p := planFor(fv.Type(), nil)
+	if len(p.in) > 1 {
+		return nil, ErrInputFuncMustTake0or1Arguments
+	}
+	if p.numOut != 1 && p.numOut != 2 {
+		return nil, fmt.Errorf("%w got: %d", ErrExpected1Or2ReturnTypes, p.numOut)
+	}
+	if p.outErr != nil {
+		return nil, p.outErr
+	}
+	st := stage{op: "lazyMap", fv: fv, p: p, filter: filter}
+	elem := s.elem
+	switch {
+	case filter:
+		if !p.returnsBool {
+			return nil, fmt.Errorf("%w instead got: %s", ErrExpectedFirstReturnToBeBool, p.out)
+		}
+		st.op = "lazyFilter"
+		if len(p.in) == 1 {
+			elem = p.in[0]
+		}
+	case p.hasErr:
+		// As with map, the type of the results of functions which can fail is inferred from the values
+		elem = nil
+	default:
+		elem = p.out
+	}
+	return &Stream{o: s.o, source: s.source, stages: append(slices.Clip(s.stages), st), elem: elem}, nil
+}
+
+// streamOf returns source as a stream, a slice or iter.Seq becoming a stream without stages.
+func (o *operations) streamOf(source any) (*Stream, error) {
+	if s, ok := source.(*Stream); ok {
+		return s, nil
+	}
+	sv := reflect.ValueOf(source)
+	switch {
+	case sv.Kind() == reflect.Invalid:
+		return &Stream{o: o, source: reflect.ValueOf([]any(nil))}, nil
+	case sv.Kind() == reflect.Slice:
+		return &Stream{o: o, source: sv, elem: sv.Type().Elem()}, nil
+	case sv.Kind() == reflect.Func && !sv.IsNil() && sv.Type().CanSeq():
+		return &Stream{o: o, source: sv, elem: sv.Type().In(0).In(0)}, nil
+	}
+	return nil, fmt.Errorf("%w not %s", ErrExpectedFirstParameterToBeSlice, sv.Kind())
+}
+
+// each passes the values of the stream to yield until it returns false or a stage fails.
+func (s *Stream) each(yield func(v reflect.Value) bool) error {
+	ee := s.o.elementErrors()
+	var err error
+	for i, v := range s.values() {
+		if err = s.o.checkContext(i); err != nil {
+			break
+		}
+		if err = s.o.checkInput(i + 1); err != nil {
+			break
+		}
+		var ok bool
+		v, ok, err = s.apply(i, v, ee)
+		if err != nil || (ok && !yield(v)) {
+			break
+		}
+	}
+	if err == nil && ee.policy == ErrorPolicyCollect {
+		err = errors.Join(ee.errs...)
+	}
+	return err
+}
+
+// values returns the values of the source with their indexes.
+func (s *Stream) values() iter.Seq2[int, reflect.Value] {
+	return func(yield func(int, reflect.Value) bool) {
+		if s.source.Kind() == reflect.Slice {
+			for i := 0; i < s.source.Len(); i++ {
+				if !yield(i, s.source.Index(i)) {
+					return
+				}
+			}
+			return
+		}
+		i := 0
+		for v := range s.source.Seq() {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// apply passes v, the value of element i, through the stages. ok is false when it is filtered out or skipped.
+func (s *Stream) apply(i int, v reflect.Value, ee *elementErrors) (_ reflect.Value, ok bool, _ error) {
+	for _, st := range s.stages {
+		var args []reflect.Value
+		var r []reflect.Value
+		var err error
+		if len(st.p.in) == 1 {
+			var arg reflect.Value
+			if arg, err = s.o.argument(i, v, st.p.in[0]); err == nil {
+				args = []reflect.Value{arg}
+			}
+		}
+		if err == nil {
+			r, err = s.o.call(st.fv, st.p, i, v, args)
+		}
+		if err == nil && st.p.hasErr && !r[1].IsNil() {
+			err = callbackError(i, v, r[1].Interface().(error))
+		}
+		if err != nil {
+			t := st.p.out
+			if st.filter {
+				t = boolType
+			}
+			sv, err := ee.handle(i, opError(st.op, st.fv.Interface(), err), t)
+			if err != nil || !sv.IsValid() {
+				return reflect.Value{}, false, err
+			}
+			r = []reflect.Value{sv}
+		}
+		switch {
+		case !st.filter:
+			v = r[0]
+		case !r[0].Bool():
+			return reflect.Value{}, false, nil
+		case args != nil:
+			// filter keeps the value as passed to f
+			v = args[0]
+		case len(st.p.in) == 1:
+			// A substitute kept a value which could not be passed to f
+			return reflect.Value{}, false, nil
+		}
+	}
+	return v, true, nil
+}
+
+// take returns the first n values of source, which is a slice, an iter.Seq or a *Stream, evaluating only as much of
+// it as needed.
+func (o *operations) take(source any, n int) (any, error) {
+	if n < 0 {
+		return nil, opError("take", nil, fmt.Errorf("%w got: %d", ErrExpectedNonNegativeCount, n))
+	}
+	s, err := o.streamOf(source)
+	if err != nil {
+		return nil, opError("take", nil, err)
+	}
+	if len(s.stages) == 0 && s.source.Kind() == reflect.Slice {
+		if err := o.checkResult(min(n, s.source.Len())); err != nil {
+			return nil, opError("take", nil, err)
+		}
+		return s.source.Slice(0, min(n, s.source.Len())).Interface(), nil
+	}
+	r, err := s.collect(n)
+	if err != nil {
+		return nil, opError("take", nil, err)
+	}
+	return r, nil
+}
+
+// collect returns the values of source, which is a slice, an iter.Seq or a *Stream, as a slice.
+func (o *operations) collect(source any) (any, error) {
+	s, err := o.streamOf(source)
+	if err != nil {
+		return nil, opError("collect", nil, err)
+	}
+	r, err := s.collect(-1)
+	if err != nil {
+		return nil, opError("collect", nil, err)
+	}
+	return r, nil
+}
+
+// collect returns up to n values of the stream, or all of them when n is negative. No more than maxEagerSeq are
+// collected from an iter.Seq.
+func (s *Stream) collect(n int) (any, error) {
+	var vs []reflect.Value
+	var elem reflect.Type
+	var err error
+	if n != 0 {
+		var tooLong error
+		err = s.each(func(v reflect.Value) bool {
+			if tooLong = s.o.checkResult(len(vs) + 1); tooLong != nil {
+				return false
+			}
+			if s.source.Kind() != reflect.Slice && len(vs) == maxEagerSeq {
+				tooLong = errSeqTooLong
+				return false
+			}
+			if s.elem == nil {
+				elem = widenType(elem, v.Type())
+			}
+			vs = append(vs, v)
+			return len(vs) != n
+		})
+		if err == nil {
+			err = tooLong
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.elem != nil {
+		elem = s.elem
+	}
+	return sliceOfValues(elem, vs).Interface(), nil
+}
@@ -0,0 +1,223 @@
+package funtemplates
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"strconv"
+	"testing"
+	"text/template"
+)
+
+func TestStreamTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "take of a lazy pipeline",
+			template:   "{{ take (lazyMap (lazyFilter $.Data $.F.Odd) $.F.Fmt) 2 }} {{ call $.Calls }}",
+			want:       "[#1 #3] 5",
+			correctErr: NoError,
+		},
+		{
+			name:       "collect",
+			template:   "{{ collect (lazyMap (lazyFilter $.Data $.F.Odd) $.F.Fmt) }}",
+			want:       "[#1 #3 #5 #7 #9]",
+			correctErr: NoError,
+		},
+		{
+			name:       "collect keeps the result type",
+			template:   `{{ printf "%T %T %T" (collect (lazyMap $.Data $.F.Fmt)) (collect (lazyFilter $.Any $.F.Odd)) (take (lazyMap $.Data $.F.Half) 2) }}`,
+			want:       "[]string []int []int",
+			correctErr: NoError,
+		},
+		{
+			name:       "range over collect",
+			template:   "{{ range collect (lazyMap $.Data $.F.Half) }}{{ . }} {{ end }}",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:     "Streams cannot be ranged over",
+			template: "{{ range lazyMap $.Data $.F.Fmt }}{{ . }}{{ end }}",
+			correctErr: func(err error) (string, bool) {
+				return "Expected:\n> an error", err != nil
+			},
+		},
+		{
+			name:       "take stops before a failing element",
+			template:   "{{ take (lazyMap $.Data $.F.Half) 2 }}",
+			want:       "[0 1]",
+			correctErr: NoError,
+		},
+		{
+			name:       "collect fails on a failing element",
+			template:   "{{ collect (lazyMap $.Data $.F.Half) }}",
+			correctErr: ErrorIs(errTest),
+		},
+		{
+			name:       "Failing elements follow the error policy",
+			opts:       []Option{WithErrorPolicy(ErrorPolicySkip)},
+			template:   "{{ take (lazyMap $.Data $.F.Half) 3 }}",
+			want:       "[0 1 2]",
+			correctErr: NoError,
+		},
+		{
+			name:       "take of a slice",
+			template:   "{{ take $.Data 3 }} {{ take $.Data 20 }}",
+			want:       "[1 2 3] [1 2 3 4 5 6 7 8 9 10]",
+			correctErr: NoError,
+		},
+		{
+			name:       "take of an iterator",
+			template:   "{{ take (seqIter 1000000000) 3 }}",
+			want:       "[0 1 2]",
+			correctErr: NoError,
+		},
+		{
+			name:       "take none",
+			template:   "{{ take (lazyMap $.Data $.F.Fmt) 0 }} {{ call $.Calls }}",
+			want:       "[] 0",
+			correctErr: NoError,
+		},
+		{
+			name:       "take a negative count",
+			template:   "{{ take $.Data -1 }}",
+			correctErr: ErrorIs(ErrExpectedNonNegativeCount),
+		},
+		{
+			name:       "lazyMap not a function",
+			template:   "{{ lazyMap $.Data 1 }}",
+			correctErr: ErrorIs(ErrExpected2ndArgumentToBeFunction),
+		},
+		{
+			name:       "lazyFilter must return bool",
+			template:   "{{ lazyFilter $.Data $.F.Fmt }}",
+			correctErr: ErrorIs(ErrExpectedFirstReturnToBeBool),
+		},
+		{
+			name:       "lazyMap not a slice",
+			template:   "{{ lazyMap 1 $.F.Fmt }}",
+			correctErr: ErrorIs(ErrExpectedFirstParameterToBeSlice),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			data := map[string]any{
+				"Data": []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+				"Any":  []any{1, 2, 3},
+				"F": map[string]any{
+					"Odd": func(i int) bool {
+						calls++
+						return i%2 == 1
+					},
+					"Fmt": func(i int) string {
+						calls++
+						return "#" + strconv.Itoa(i)
+					},
+					"Half": func(i int) (int, error) {
+						if i%3 == 0 {
+							return 0, errTest
+						}
+						return i / 2, nil
+					},
+				},
+			}
+			data["Calls"] = func() int { return calls }
+			tmpl := template.Must(template.New("").Funcs(TextFunctions(tt.opts...)).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("stream got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("stream diff =\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestStreamValues(t *testing.T) {
+	calls := 0
+	s, err := LazyMapTemplateFunc([]int{1, 2, 3}, func(i int) (int, error) {
+		calls++
+		if i == 3 {
+			return 0, errTest
+		}
+		return i, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v, err := range StreamValues(s) {
+		if v != 1 || err != nil {
+			t.Errorf("expected 1, got %v %v", v, err)
+		}
+		break
+	}
+	if calls != 1 {
+		t.Errorf("expected ranging to stop after 1 call, got %d", calls)
+	}
+	var got []any
+	var last error
+	for v, err := range StreamValues(s) {
+		got = append(got, v)
+		last = err
+	}
+	if diff := cmp.Diff([]any{1, 2, nil}, got); diff != "" {
+		t.Errorf("values diff =\n %s", diff)
+	}
+	if !errors.Is(last, errTest) {
+		t.Errorf("expected the last pair to hold the error, got %v", last)
+	}
+}
+
+func BenchmarkTakeOfPipeline(b *testing.B) {
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = i
+	}
+	ok := func(i int) bool { return i%2 == 0 }
+	format := func(i int) string { return strconv.Itoa(i) }
+	b.Run("eager", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filtered, err := FilterTemplateFunc(data, ok)
+			if err != nil {
+				b.Fatal(err)
+			}
+			mapped, err := MapTemplateFunc(filtered, format)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := TakeTemplateFunc(mapped, 10); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("lazy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filtered, err := LazyFilterTemplateFunc(data, ok)
+			if err != nil {
+				b.Fatal(err)
+			}
+			mapped, err := LazyMapTemplateFunc(filtered, format)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := TakeTemplateFunc(mapped, 10); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
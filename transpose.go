@@ -0,0 +1,101 @@
+package funtemplates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type RaggedPolicy string
+
+const (
+	RaggedError    RaggedPolicy = "error"
+	RaggedPad      RaggedPolicy = "pad"
+	RaggedTruncate RaggedPolicy = "truncate"
+)
+
+// TransposeTemplateFunc swaps the rows and columns of a slice of slices. Rows of differing lengths are an
+// ErrRaggedRows unless policy pads short rows with zero values or truncates to the shortest row.
+func TransposeTemplateFunc(matrix any, policy ...RaggedPolicy) (any, error) {
+	return defaultOperations.transpose(matrix, policy...)
+}
+
+func (o *operations) transpose(matrix any, policy ...RaggedPolicy) (any, error) {
+	r, err := o.transposeRows(matrix, policy)
+	if err != nil {
+		return nil, opError("transpose", nil, err)
+	}
+	return r, nil
+}
+
+func (o *operations) transposeRows(matrix any, policy []RaggedPolicy) (any, error) {
+	p := RaggedError
+	switch len(policy) {
+	case 0:
+	case 1:
+		p = policy[0]
+	default:
+		return nil, fmt.Errorf("%w got: %d", ErrExpectedAtMostOnePolicy, len(policy))
+	}
+	switch p {
+	case RaggedError, RaggedPad, RaggedTruncate:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPolicy, p)
+	}
+	av, l, err := o.sliceArg(matrix)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]reflect.Value, l)
+	width := -1
+	for i := 0; i < l; i++ {
+		if err := o.checkContext(i); err != nil {
+			return nil, err
+		}
+		row := indirectInterface(av.Index(i))
+		if row.IsValid() && row.Kind() != reflect.Slice {
+			return nil, elementError(i, row, fmt.Errorf("%w not %s", ErrExpectedSliceOfSlices, row.Kind()))
+		}
+		rows[i] = row
+		rl := 0
+		if row.IsValid() {
+			rl = row.Len()
+		}
+		switch {
+		case width == -1:
+			width = rl
+		case rl == width:
+		case p == RaggedError:
+			return nil, elementError(i, row, fmt.Errorf("%w: length %d, expected %d", ErrRaggedRows, rl, width))
+		case p == RaggedPad:
+			width = max(width, rl)
+		case p == RaggedTruncate:
+			width = min(width, rl)
+		}
+	}
+	width = max(width, 0)
+	if err := o.checkResult(width); err != nil {
+		return nil, err
+	}
+
+	elemType := anyType
+	if av.IsValid() && av.Type().Elem().Kind() == reflect.Slice {
+		elemType = av.Type().Elem().Elem()
+	} else if l > 0 {
+		elemType = sharedElemType(rows)
+	}
+	columnType := reflect.SliceOf(elemType)
+	nra := reflect.MakeSlice(reflect.SliceOf(columnType), width, width)
+	for c := 0; c < width; c++ {
+		if err := o.checkContext(c); err != nil {
+			return nil, err
+		}
+		column := reflect.MakeSlice(columnType, l, l)
+		for r, row := range rows {
+			if row.IsValid() && c < row.Len() {
+				column.Index(r).Set(row.Index(c))
+			}
+		}
+		nra.Index(c).Set(column)
+	}
+	return nra.Interface(), nil
+}
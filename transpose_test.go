@@ -0,0 +1,113 @@
+package funtemplates
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"text/template"
+)
+
+func TestTransposeTemplateFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		correctErr func(err error) (string, bool)
+	}{
+		{
+			name:       "Transpose",
+			template:   "{{ transpose $.Matrix }}",
+			want:       "[[1 4] [2 5] [3 6]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Inner type is preserved",
+			template:   `{{ printf "%T" (transpose $.Matrix) }}`,
+			want:       "[][]int",
+			correctErr: NoError,
+		},
+		{
+			name:       "Interface rows sharing a type",
+			template:   `{{ printf "%T %v" (transpose $.AnyRows) (transpose $.AnyRows) }}`,
+			want:       "[][]string [[a c] [b d]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Interface rows of mixed types",
+			template:   `{{ printf "%T %v" (transpose $.MixedRows) (transpose $.MixedRows) }}`,
+			want:       "[][]interface {} [[a 1] [b 2]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Ragged rows are an error",
+			template:   "{{ transpose $.Ragged }}",
+			want:       "",
+			correctErr: ErrorIs(ErrRaggedRows),
+		},
+		{
+			name:       "Ragged rows padded",
+			template:   `{{ transpose $.Ragged "pad" }}`,
+			want:       "[[1 3 4] [2 0 5] [0 0 6]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Ragged rows truncated",
+			template:   `{{ transpose $.Ragged "truncate" }}`,
+			want:       "[[1 3 4]]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Empty",
+			template:   "{{ transpose nil }}",
+			want:       "[]",
+			correctErr: NoError,
+		},
+		{
+			name:       "Rows must be slices",
+			template:   "{{ transpose $.NotRows }}",
+			want:       "",
+			correctErr: ErrorIs(ErrExpectedSliceOfSlices),
+		},
+		{
+			name:       "Unknown policy",
+			template:   `{{ transpose $.Matrix "wrap" }}`,
+			want:       "",
+			correctErr: ErrorIs(ErrUnknownPolicy),
+		},
+	}
+	funcs := TextFunctions()
+	data := struct {
+		Matrix    [][]int
+		AnyRows   []any
+		MixedRows []any
+		Ragged    [][]int
+		NotRows   []any
+	}{
+		Matrix:    [][]int{{1, 2, 3}, {4, 5, 6}},
+		AnyRows:   []any{[]string{"a", "b"}, []string{"c", "d"}},
+		MixedRows: []any{[]string{"a", "b"}, []int{1, 2}},
+		Ragged:    [][]int{{1, 2}, {3}, {4, 5, 6}},
+		NotRows:   []any{1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New("").Funcs(funcs).Parse(tt.template))
+			got := bytes.NewBuffer(nil)
+			err := tmpl.Execute(got, data)
+			if tt.correctErr != nil {
+				if description, ok := tt.correctErr(err); !ok {
+					t.Errorf("TransposeTemplateFunc() got error =\n> %v\n\n%s", err, description)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("TransposeTemplateFunc() diff =\n %s", diff)
+			}
+		})
+	}
+}